@@ -1,21 +1,36 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexedwards/scs/postgresstore"
 	"github.com/alexedwards/scs/v2"
 	"github.com/anubhav047/goboard/internal/db"
 	httphandlers "github.com/anubhav047/goboard/internal/http"
+	"github.com/anubhav047/goboard/internal/mail"
+	accesstokenservice "github.com/anubhav047/goboard/internal/services/accesstoken"
+	accountservice "github.com/anubhav047/goboard/internal/services/account"
 	boardservice "github.com/anubhav047/goboard/internal/services/board"
 	cardservice "github.com/anubhav047/goboard/internal/services/card"
+	invitationservice "github.com/anubhav047/goboard/internal/services/invitation"
 	listservice "github.com/anubhav047/goboard/internal/services/list"
+	"github.com/anubhav047/goboard/internal/services/loginguard"
+	notificationservice "github.com/anubhav047/goboard/internal/services/notification"
+	reminderservice "github.com/anubhav047/goboard/internal/services/reminder"
+	sessionservice "github.com/anubhav047/goboard/internal/services/session"
+	ssoservice "github.com/anubhav047/goboard/internal/services/sso"
+	trashservice "github.com/anubhav047/goboard/internal/services/trash"
 	userservice "github.com/anubhav047/goboard/internal/services/user"
+	workspaceservice "github.com/anubhav047/goboard/internal/services/workspace"
+	"github.com/anubhav047/goboard/internal/token"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/joho/godotenv"
 )
@@ -55,6 +70,29 @@ func main() {
 
 	log.Println("Database connection successful.")
 
+	// Secret used to sign emailed tokens
+	appSecret := os.Getenv("APP_SECRET")
+	if appSecret == "" {
+		log.Println("APP_SECRET is not set, using a random secret. Emailed links will stop working on restart.")
+		appSecret, _, err = token.Generate()
+		if err != nil {
+			log.Fatalf("Unable to generate app secret: %v\n", err)
+		}
+	}
+	signer := token.NewSigner([]byte(appSecret))
+
+	// Frontend address used to build links in emails
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+
+	// MAILER
+	mailer, err := newMailer()
+	if err != nil {
+		log.Fatalf("Unable to create mailer: %v\n", err)
+	}
+
 	// SESSION MANAGER
 	sessionManager := scs.New()
 	sessionManager.Store = postgresstore.New(dbConn)
@@ -66,8 +104,54 @@ func main() {
 	// Create a Queries object from the connection pool
 	queries := db.New(dbpool)
 
+	// Password policy, tunable with PASSWORD_MIN_LENGTH and PASSWORD_CHECK_BREACHED
+	passwordPolicy := userservice.DefaultPasswordPolicy()
+	if v := os.Getenv("PASSWORD_MIN_LENGTH"); v != "" {
+		passwordPolicy.MinLength, err = strconv.Atoi(v)
+		if err != nil || passwordPolicy.MinLength < 1 {
+			log.Fatalf("Invalid PASSWORD_MIN_LENGTH: %q\n", v)
+		}
+	}
+	if os.Getenv("PASSWORD_CHECK_BREACHED") == "false" {
+		passwordPolicy.CheckBreached = false
+	}
+
 	// Create the user Service
-	userService := userservice.New(queries)
+	userService := userservice.New(queries, mailer, userservice.Config{
+		BaseURL:        appURL,
+		ResetTokenTTL:  time.Hour,
+		VerifyTokenTTL: 24 * time.Hour,
+		PasswordPolicy: passwordPolicy,
+	})
+
+	// Create the session Service
+	sessionService := sessionservice.New(queries)
+
+	// Create the login guard. Counters are kept in Postgres unless LOGIN_GUARD_STORE=memory,
+	// which only suits a single server instance.
+	var guardStore loginguard.Store = loginguard.NewPostgresStore(queries)
+	if os.Getenv("LOGIN_GUARD_STORE") == "memory" {
+		guardStore = loginguard.NewMemoryStore()
+	}
+	loginGuard := loginguard.New(guardStore, queries, loginguard.DefaultConfig())
+	resetLimiter := loginguard.NewLimiter(guardStore, "reset", loginguard.LimiterConfig{PerEmail: 3, PerIP: 20, Window: time.Hour})
+	go pruneLoginAttempts(loginGuard)
+
+	// Create the account Service
+	accountService := accountservice.New(queries)
+
+	// Create the access token Service
+	tokenService := accesstokenservice.New(queries)
+
+	// Create the SSO Service
+	providers, err := oidcProviders()
+	if err != nil {
+		log.Fatalf("Invalid OIDC configuration: %v\n", err)
+	}
+	ssoService, err := ssoservice.New(context.Background(), queries, providers)
+	if err != nil {
+		log.Fatalf("Unable to set up SSO: %v\n", err)
+	}
 
 	// Create the board Service
 	boardService := boardservice.New(queries)
@@ -77,12 +161,71 @@ func main() {
 
 	// Create the card Service
 	cardService := cardservice.New(queries)
+	go rebalanceRanks(cardService, listService)
+
+	// Create the notification Service
+	notificationService := notificationservice.New(queries, mailer)
+
+	// Create the reminder Service. Due reminders are sent from every instance;
+	// each one is claimed in the database so it only goes out once.
+	reminderService := reminderservice.New(queries, notificationService)
+	go dispatchReminders(reminderService)
+
+	// Create the trash Service. Deleted items are purged after TRASH_RETENTION, 30 days by default.
+	trashService := trashservice.New(queries)
+	trashRetention := 30 * 24 * time.Hour
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		trashRetention, err = time.ParseDuration(v)
+		if err != nil || trashRetention <= 0 {
+			log.Fatalf("Invalid TRASH_RETENTION: %q\n", v)
+		}
+	}
+	go purgeTrash(trashService, trashRetention)
+
+	// Create the workspace Service
+	workspaceService := workspaceservice.New(queries)
+
+	// Create the invitation Service
+	invitationService := invitationservice.New(queries, signer, mailer, appURL)
+
+	// What users with an unverified email may do: allow, no-create or read-only
+	unverifiedPolicy := httphandlers.UnverifiedAllow
+	if v := os.Getenv("UNVERIFIED_EMAIL_POLICY"); v != "" {
+		unverifiedPolicy, err = httphandlers.ParseUnverifiedPolicy(v)
+		if err != nil {
+			log.Fatalf("Invalid UNVERIFIED_EMAIL_POLICY: %v\n", err)
+		}
+	}
+
+	// How request bodies are read, tunable with MAX_BODY_BYTES and ALLOW_UNKNOWN_FIELDS
+	decodeOptions := httphandlers.DefaultDecodeOptions()
+	if v := os.Getenv("MAX_BODY_BYTES"); v != "" {
+		decodeOptions.MaxBodyBytes, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || decodeOptions.MaxBodyBytes < 1 {
+			log.Fatalf("Invalid MAX_BODY_BYTES: %q\n", v)
+		}
+	}
+	if os.Getenv("ALLOW_UNKNOWN_FIELDS") == "true" {
+		decodeOptions.DisallowUnknownFields = false
+	}
+	httphandlers.SetDecodeOptions(decodeOptions)
+
+	// Reverse proxies whose X-Forwarded-For header gives the client address, as a
+	// comma-separated list of IPs and CIDR ranges in TRUSTED_PROXIES. Without it the
+	// header is ignored, since clients could use it to dodge the login limits.
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		proxies, err := httphandlers.ParseTrustedProxies(v)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES: %v\n", err)
+		}
+		httphandlers.SetTrustedProxies(proxies)
+	}
 
 	// Create middleware struct
-	mw := httphandlers.NewMiddleware(sessionManager, queries)
+	mw := httphandlers.NewMiddleware(sessionManager, queries, sessionService, tokenService, unverifiedPolicy)
 
 	// Create and register User Handler
-	userHandler := httphandlers.NewUserHandler(userService, sessionManager)
+	userHandler := httphandlers.NewUserHandler(userService, sessionService, loginGuard, resetLimiter, sessionManager)
 
 	// Create and register Board Handler
 	boardHandler := httphandlers.NewBoardHandler(boardService)
@@ -93,16 +236,156 @@ func main() {
 	// Create and register Card Handler
 	cardHandler := httphandlers.NewCardHandler(cardService)
 
+	// Create and register Trash Handler
+	trashHandler := httphandlers.NewTrashHandler(trashService)
+
+	// Create and register Notification Handler
+	notificationHandler := httphandlers.NewNotificationHandler(notificationService)
+
+	// Create and register Workspace Handler
+	workspaceHandler := httphandlers.NewWorkspaceHandler(workspaceService)
+
+	// Create and register Invitation Handler
+	invitationHandler := httphandlers.NewInvitationHandler(invitationService)
+
+	// Create and register Token Handler
+	tokenHandler := httphandlers.NewTokenHandler(tokenService)
+
+	// Create and register SSO Handler
+	ssoHandler := httphandlers.NewSSOHandler(ssoService, sessionService, sessionManager, appURL)
+
+	// Create and register Account Handler
+	accountHandler := httphandlers.NewAccountHandler(accountService, sessionManager)
+
 	mux := http.NewServeMux()
 	userHandler.RegisterRoutes(mux, mw)
 	boardHandler.RegisterRoutes(mux, mw)
 	listHandler.RegisterRoutes(mux, mw)
 	cardHandler.RegisterRoutes(mux, mw)
+	trashHandler.RegisterRoutes(mux, mw)
+	notificationHandler.RegisterRoutes(mux, mw)
+	workspaceHandler.RegisterRoutes(mux, mw)
+	invitationHandler.RegisterRoutes(mux, mw)
+	tokenHandler.RegisterRoutes(mux, mw)
+	ssoHandler.RegisterRoutes(mux, mw)
+	accountHandler.RegisterRoutes(mux, mw)
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, "ok")
 	})
 
 	log.Println("Server running on :8080")
 	log.Fatal(http.ListenAndServe(":8080", sessionManager.LoadAndSave(mux)))
+}
+
+// newMailer picks the mailer based on the environment.
+// SMTP is used when SMTP_HOST is set, otherwise emails are written to MAIL_DIR or the log.
+func newMailer() (mail.Mailer, error) {
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		from := os.Getenv("MAIL_FROM")
+		if from == "" {
+			from = "goboard@localhost"
+		}
+		return mail.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	}
+
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		return mail.NewFileMailer(dir)
+	}
+
+	return mail.LogMailer{}, nil
+}
+
+// pruneLoginAttempts periodically forgets expired failed login streaks
+func pruneLoginAttempts(guard *loginguard.Guard) {
+	for range time.Tick(time.Hour) {
+		if err := guard.Prune(context.Background()); err != nil {
+			log.Printf("Failed to prune login attempts: %v", err)
+		}
+	}
+}
+
+// maxRankLength is how long card and list ranks may grow before they are respaced
+const maxRankLength = 24
+
+// rebalanceRanks periodically respaces card and list ranks that have grown long
+// from repeated inserts at the same spot
+func rebalanceRanks(cards *cardservice.Service, lists *listservice.Service) {
+	for range time.Tick(time.Hour) {
+		n, err := cards.RebalanceRanks(context.Background(), maxRankLength)
+		if err != nil {
+			log.Printf("Failed to rebalance card ranks: %v", err)
+		} else if n > 0 {
+			log.Printf("Rebalanced card ranks in %d lists", n)
+		}
+
+		n, err = lists.RebalanceRanks(context.Background(), maxRankLength)
+		if err != nil {
+			log.Printf("Failed to rebalance list ranks: %v", err)
+		} else if n > 0 {
+			log.Printf("Rebalanced list ranks on %d boards", n)
+		}
+	}
+}
+
+// purgeTrash periodically deletes items that have been in the trash for longer than retention
+func purgeTrash(trash *trashservice.Service, retention time.Duration) {
+	for range time.Tick(time.Hour) {
+		n, err := trash.Purge(context.Background(), retention)
+		if err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		} else if n > 0 {
+			log.Printf("Purged %d items from the trash", n)
+		}
+	}
+}
+
+// dispatchReminders sends due date reminders every minute, at most 500 per tick
+func dispatchReminders(reminders *reminderservice.Service) {
+	for range time.Tick(time.Minute) {
+		n, err := reminders.DispatchDue(context.Background(), 500)
+		if err != nil {
+			log.Printf("Failed to send reminders: %v", err)
+		} else if n > 0 {
+			log.Printf("Sent %d due date reminders", n)
+		}
+	}
+}
+
+// oidcProviders reads the identity providers from the environment.
+// OIDC_PROVIDERS is a comma-separated list of names; each name NAME is configured with
+// OIDC_NAME_ISSUER, OIDC_NAME_CLIENT_ID, OIDC_NAME_CLIENT_SECRET and optionally OIDC_NAME_SCOPES.
+// Callbacks are served at API_URL/api/auth/oidc/name/callback.
+func oidcProviders() ([]ssoservice.ProviderConfig, error) {
+	apiURL := strings.TrimRight(os.Getenv("API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "http://localhost:8080"
+	}
+
+	var providers []ssoservice.ProviderConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := ssoservice.ProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  apiURL + "/api/auth/oidc/" + name + "/callback",
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID must be set", prefix, prefix)
+		}
+		providers = append(providers, cfg)
+	}
 
+	return providers, nil
 }
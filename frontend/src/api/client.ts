@@ -22,6 +22,7 @@ export interface Board {
   Name: string;
   Description: string;
   CreatedBy: number;
+  ArchivedAt: string | null;
   CreatedAt: string;
   UpdatedAt: string;
 }
@@ -30,7 +31,8 @@ export interface List {
   ID: number;
   Name: string;
   BoardID: number;
-  Position: number;
+  Rank: string;
+  ArchivedAt: string | null;
   CreatedAt: string;
   UpdatedAt: string;
 }
@@ -40,11 +42,83 @@ export interface Card {
   Title: string;
   Description: string;
   ListID: number;
-  Position: number;
+  Rank: string;
+  // Dates are UTC timestamps
+  StartAt: string | null;
+  DueAt: string | null;
+  Completed: boolean;
+  CompletedAt: string | null;
+  ArchivedAt: string | null;
   CreatedAt: string;
   UpdatedAt: string;
 }
 
+export interface Label {
+  ID: number;
+  BoardID: number;
+  Name: string;
+  Color: string;
+  CreatedAt: string;
+  UpdatedAt: string;
+}
+
+export type LabeledCard = Card & { Labels: Label[] };
+
+export interface Assignee {
+  CardID: number;
+  UserID: number;
+  AssignedBy: number | null;
+  CreatedAt: string;
+  Name: string;
+  Email: string;
+}
+
+export interface AssignedBoard {
+  ID: number;
+  Name: string;
+  Lists: { ID: number; Name: string; Cards: Card[] }[];
+}
+
+export type DueCard = Card & { BoardID: number; BoardName: string; ListName: string };
+
+export interface CardSchedule {
+  // Timestamps need a time zone, like 2026-01-02T15:04:05Z; null clears the date
+  start_at?: string | null;
+  due_at?: string | null;
+  completed?: boolean;
+}
+
+export interface Reminder {
+  ID: number;
+  CardID: number;
+  OffsetSeconds: number;
+  // Due date the reminder last fired for; a new due date re-arms it
+  SentForDueAt: string | null;
+  CreatedBy: number | null;
+  CreatedAt: string;
+}
+
+export interface Notification {
+  ID: number;
+  UserID: number;
+  CardID: number | null;
+  Kind: string;
+  Message: string;
+  ReadAt: string | null;
+  CreatedAt: string;
+}
+
+export interface Trash {
+  Boards: Board[];
+  Lists: List[];
+  Cards: Card[];
+}
+
+export interface BoardSnapshot extends Board {
+  Labels: Label[];
+  Lists: (List & { Cards: LabeledCard[] })[];
+}
+
 // Auth API
 export const authAPI = {
   register: async (name: string, email: string, password: string): Promise<User> => {
@@ -65,8 +139,8 @@ export const authAPI = {
 
 // Boards API
 export const boardsAPI = {
-  getAll: async (): Promise<Board[]> => {
-    const response = await api.get('/boards');
+  getAll: async (archived = false): Promise<Board[]> => {
+    const response = await api.get('/boards', { params: archived ? { archived } : undefined });
     return response.data;
   },
 
@@ -75,6 +149,12 @@ export const boardsAPI = {
     return response.data;
   },
 
+  // Gets the board with its lists and their cards in one request
+  getFull: async (id: number): Promise<BoardSnapshot> => {
+    const response = await api.get(`/boards/${id}/full`, { params: { include: 'labels' } });
+    return response.data;
+  },
+
   create: async (name: string, description: string): Promise<Board> => {
     const response = await api.post('/boards', { name, description });
     return response.data;
@@ -85,6 +165,17 @@ export const boardsAPI = {
     return response.data;
   },
 
+  archive: async (id: number): Promise<Board> => {
+    const response = await api.post(`/boards/${id}/archive`);
+    return response.data;
+  },
+
+  unarchive: async (id: number): Promise<Board> => {
+    const response = await api.post(`/boards/${id}/unarchive`);
+    return response.data;
+  },
+
+  // Only archived items can be deleted; they go to the trash
   delete: async (id: number): Promise<void> => {
     await api.delete(`/boards/${id}`);
   },
@@ -92,21 +183,38 @@ export const boardsAPI = {
 
 // Lists API
 export const listsAPI = {
-  getByBoard: async (boardId: number): Promise<List[]> => {
-    const response = await api.get(`/boards/${boardId}/lists`);
+  getByBoard: async (boardId: number, archived = false): Promise<List[]> => {
+    const response = await api.get(`/boards/${boardId}/lists`, { params: archived ? { archived } : undefined });
+    return response.data;
+  },
+
+  create: async (boardId: number, name: string): Promise<List> => {
+    const response = await api.post(`/boards/${boardId}/lists`, { name });
+    return response.data;
+  },
+
+  update: async (id: number, name: string): Promise<List> => {
+    const response = await api.put(`/lists/${id}`, { name });
     return response.data;
   },
 
-  create: async (boardId: number, name: string, position: number): Promise<List> => {
-    const response = await api.post(`/boards/${boardId}/lists`, { name, position });
+  // Places the list before the list beforeId, or at the end of the board when it is omitted
+  move: async (id: number, boardId: number, beforeId?: number): Promise<List> => {
+    const response = await api.put(`/lists/${id}/move`, { board_id: boardId, before_id: beforeId });
     return response.data;
   },
 
-  update: async (id: number, name: string, position: number): Promise<List> => {
-    const response = await api.put(`/lists/${id}`, { name, position });
+  archive: async (id: number): Promise<List> => {
+    const response = await api.post(`/lists/${id}/archive`);
     return response.data;
   },
 
+  unarchive: async (id: number): Promise<List> => {
+    const response = await api.post(`/lists/${id}/unarchive`);
+    return response.data;
+  },
+
+  // Only archived items can be deleted; they go to the trash
   delete: async (id: number): Promise<void> => {
     await api.delete(`/lists/${id}`);
   },
@@ -114,29 +222,170 @@ export const listsAPI = {
 
 // Cards API
 export const cardsAPI = {
-  getByList: async (listId: number): Promise<Card[]> => {
-    const response = await api.get(`/lists/${listId}/cards`);
+  // Only cards with any of labelIds are returned when it is not empty
+  getByList: async (listId: number, archived = false, labelIds: number[] = []): Promise<LabeledCard[]> => {
+    const params: Record<string, string | boolean> = {};
+    if (archived) params.archived = true;
+    if (labelIds.length > 0) params.labels = labelIds.join(',');
+    const response = await api.get(`/lists/${listId}/cards`, { params });
+    return response.data;
+  },
+
+  create: async (listId: number, title: string, description: string): Promise<Card> => {
+    const response = await api.post(`/lists/${listId}/cards`, { title, description });
+    return response.data;
+  },
+
+  // Fields left out of schedule are not changed
+  update: async (id: number, title: string, description: string, schedule: CardSchedule = {}): Promise<Card> => {
+    const response = await api.put(`/cards/${id}`, { title, description, ...schedule });
+    return response.data;
+  },
+
+  // within is a duration like '48h'
+  dueSoon: async (within?: string): Promise<DueCard[]> => {
+    const response = await api.get('/me/cards/due-soon', { params: within ? { within } : undefined });
+    return response.data;
+  },
+
+  overdue: async (): Promise<DueCard[]> => {
+    const response = await api.get('/me/cards/overdue');
     return response.data;
   },
 
-  create: async (listId: number, title: string, description: string, position: number): Promise<Card> => {
-    const response = await api.post(`/lists/${listId}/cards`, { title, description, position });
+  // Places the card before the card beforeId, or at the end of the list when it is omitted
+  move: async (id: number, listId: number, beforeId?: number): Promise<Card> => {
+    const response = await api.put(`/cards/${id}/move`, { list_id: listId, before_id: beforeId });
     return response.data;
   },
 
-  update: async (id: number, title: string, description: string): Promise<Card> => {
-    const response = await api.put(`/cards/${id}`, { title, description });
+  archive: async (id: number): Promise<Card> => {
+    const response = await api.post(`/cards/${id}/archive`);
     return response.data;
   },
 
-  move: async (id: number, listId: number, position: number): Promise<Card> => {
-    const response = await api.put(`/cards/${id}/move`, { list_id: listId, position });
+  unarchive: async (id: number): Promise<Card> => {
+    const response = await api.post(`/cards/${id}/unarchive`);
     return response.data;
   },
 
+  // Only archived items can be deleted; they go to the trash
   delete: async (id: number): Promise<void> => {
     await api.delete(`/cards/${id}`);
   },
 };
 
+// Labels API
+export const labelsAPI = {
+  getByBoard: async (boardId: number): Promise<Label[]> => {
+    const response = await api.get(`/boards/${boardId}/labels`);
+    return response.data;
+  },
+
+  create: async (boardId: number, name: string, color: string): Promise<Label> => {
+    const response = await api.post(`/boards/${boardId}/labels`, { name, color });
+    return response.data;
+  },
+
+  update: async (boardId: number, id: number, name: string, color: string): Promise<Label> => {
+    const response = await api.put(`/boards/${boardId}/labels/${id}`, { name, color });
+    return response.data;
+  },
+
+  delete: async (boardId: number, id: number): Promise<void> => {
+    await api.delete(`/boards/${boardId}/labels/${id}`);
+  },
+
+  // Both return the card's labels
+  attach: async (cardId: number, id: number): Promise<Label[]> => {
+    const response = await api.put(`/cards/${cardId}/labels/${id}`);
+    return response.data;
+  },
+
+  detach: async (cardId: number, id: number): Promise<Label[]> => {
+    const response = await api.delete(`/cards/${cardId}/labels/${id}`);
+    return response.data;
+  },
+};
+
+// Assignees API
+export const assigneesAPI = {
+  getByCard: async (cardId: number): Promise<Assignee[]> => {
+    const response = await api.get(`/cards/${cardId}/assignees`);
+    return response.data;
+  },
+
+  // Both return the card's assignees
+  assign: async (cardId: number, userId: number): Promise<Assignee[]> => {
+    const response = await api.put(`/cards/${cardId}/assignees/${userId}`);
+    return response.data;
+  },
+
+  unassign: async (cardId: number, userId: number): Promise<Assignee[]> => {
+    const response = await api.delete(`/cards/${cardId}/assignees/${userId}`);
+    return response.data;
+  },
+
+  // Cards assigned to the current user, grouped by board and list
+  mine: async (): Promise<AssignedBoard[]> => {
+    const response = await api.get('/me/cards');
+    return response.data;
+  },
+};
+
+// Reminders API
+export const remindersAPI = {
+  get: async (cardId: number): Promise<Reminder[]> => {
+    const response = await api.get(`/cards/${cardId}/reminders`);
+    return response.data;
+  },
+
+  // before is a duration like "1h" or "24h"
+  add: async (cardId: number, before: string): Promise<Reminder> => {
+    const response = await api.post(`/cards/${cardId}/reminders`, { before });
+    return response.data;
+  },
+
+  delete: async (cardId: number, reminderId: number): Promise<void> => {
+    await api.delete(`/cards/${cardId}/reminders/${reminderId}`);
+  },
+};
+
+// Notifications API
+export const notificationsAPI = {
+  get: async (unreadOnly = false): Promise<Notification[]> => {
+    const response = await api.get('/me/notifications', { params: unreadOnly ? { unread: true } : undefined });
+    return response.data;
+  },
+
+  markRead: async (id: number): Promise<Notification> => {
+    const response = await api.post(`/me/notifications/${id}/read`);
+    return response.data;
+  },
+};
+
+// Trash API
+export const trashAPI = {
+  get: async (): Promise<Trash> => {
+    const response = await api.get('/trash');
+    return response.data;
+  },
+
+  restoreBoard: async (id: number): Promise<Board> => {
+    const response = await api.post(`/trash/boards/${id}/restore`);
+    return response.data;
+  },
+
+  // Brings back the list's cards too
+  restoreList: async (id: number): Promise<List> => {
+    const response = await api.post(`/trash/lists/${id}/restore`);
+    return response.data;
+  },
+
+  restoreCard: async (id: number): Promise<Card> => {
+    const response = await api.post(`/trash/cards/${id}/restore`);
+    return response.data;
+  },
+};
+
 export default api;
@@ -126,22 +126,11 @@ async function showBoard(boardId: number) {
   try {
     console.log('Loading board with ID:', boardId);
     
-    // Get board details and lists
-    const [board, lists] = await Promise.all([
-      boardsAPI.getById(boardId),
-      listsAPI.getByBoard(boardId)
-    ]);
-    
-    console.log('Board loaded:', board);
-    console.log('Lists loaded:', lists);
+    // Get the board with its lists and cards
+    const board = await boardsAPI.getFull(boardId);
+    const listsWithCards = board.Lists;
 
-    // Get cards for each list
-    const listsWithCards = await Promise.all(
-      lists.map(async (list) => {
-        const cards = await cardsAPI.getByList(list.ID);
-        return { ...list, cards: cards || [] };
-      })
-    );
+    console.log('Board loaded:', board);
 
     mainContent.innerHTML = `
       <div class="container">
@@ -161,7 +150,7 @@ async function showBoard(boardId: number) {
                 <button onclick="showCreateCardModal(${list.ID})" class="btn" style="padding: 0.5rem; font-size: 0.8rem;">+ Add Card</button>
               </div>
               <div class="cards-container">
-                ${(list.cards || []).map(card => `
+                ${list.Cards.map(card => `
                   <div class="card-item" data-card-id="${card.ID}" draggable="true">
                     <div class="card-title">${card.Title}</div>
                     ${card.Description ? `<div class="card-description">${card.Description}</div>` : ''}
@@ -377,11 +366,8 @@ async function handleCreateList(e: Event) {
   const boardId = (window as any).currentBoardId;
 
   try {
-    // Get current lists to determine position
-    const lists = await listsAPI.getByBoard(boardId);
-    const position = lists.length;
-
-    await listsAPI.create(boardId, name, position);
+    // New lists go to the end of the board
+    await listsAPI.create(boardId, name);
     hideCreateListModal();
     showBoard(boardId); // Refresh board view
   } catch (error: any) {
@@ -398,11 +384,8 @@ async function handleCreateCard(e: Event) {
   const listId = (window as any).currentListId;
 
   try {
-    // Get current cards to determine position
-    const cards = await cardsAPI.getByList(listId);
-    const position = cards.length;
-
-    await cardsAPI.create(listId, title, description || '', position);
+    // New cards go to the end of the list
+    await cardsAPI.create(listId, title, description || '');
     hideCreateCardModal();
     showBoard((window as any).currentBoardId); // Refresh board view
   } catch (error: any) {
@@ -448,13 +431,11 @@ function initializeDragAndDrop() {
 
 // let draggedCard: HTMLElement | null = null; // Unused for now but may be needed for advanced features
 let draggedCardId: string | null = null;
-let draggedFromListId: string | null = null;
 
 function handleDragStart(e: DragEvent) {
   const card = e.target as HTMLElement;
   // draggedCard = card; // Store for potential future use
   draggedCardId = (card as any).dataset.cardId || null;
-  draggedFromListId = (card.closest('.list') as any)?.dataset.listId || null;
   
   card.classList.add('dragging');
   
@@ -471,7 +452,6 @@ function handleDragEnd(e: DragEvent) {
   // Clean up
   // draggedCard = null;
   draggedCardId = null;
-  draggedFromListId = null;
   
   // Remove drag-over classes from all containers and cards
   document.querySelectorAll('.cards-container').forEach(container => {
@@ -572,28 +552,12 @@ async function handleDrop(e: DragEvent) {
   });
   
   try {
-    let newPosition: number;
-    
-    // If dropped in the same list, calculate position based on drop location
-    if (targetListId === draggedFromListId) {
-      // Find the drop position based on mouse position
-      const cards = Array.from(container.querySelectorAll('.card-item'));
-      const afterElement = getDragAfterElement(container, e.clientY);
-      
-      if (afterElement == null) {
-        newPosition = cards.length - 1; // Drop at end
-      } else {
-        const afterIndex = cards.indexOf(afterElement);
-        newPosition = afterIndex;
-      }
-    } else {
-      // Different list - append to end
-      const targetCards = container.querySelectorAll('.card-item');
-      newPosition = targetCards.length;
-    }
-    
+    // Drop the card before the card under the pointer, or at the end of the list
+    const beforeElement = getDragAfterElement(container, e.clientY);
+    const beforeId = beforeElement ? parseInt((beforeElement as any).dataset.cardId) : undefined;
+
     // Call API to move the card
-    await cardsAPI.move(parseInt(draggedCardId), parseInt(targetListId), newPosition);
+    await cardsAPI.move(parseInt(draggedCardId), parseInt(targetListId), beforeId);
     
     // Refresh the board view
     showBoard((window as any).currentBoardId);
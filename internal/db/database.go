@@ -3,13 +3,38 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Connect creates a new database connection pool.
 func Connect(connStr string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(context.Background(), connStr)
+	config, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection string: %w", err)
+	}
+
+	return ConnectConfig(config)
+}
+
+// ConnectConfig creates a new database connection pool from a parsed configuration,
+// with the same settings as Connect.
+func ConnectConfig(config *pgxpool.Config) (*pgxpool.Pool, error) {
+	// Timestamps are always handled in UTC, whatever the server or process time zone
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		conn.TypeMap().RegisterType(&pgtype.Type{
+			Name:  "timestamptz",
+			OID:   pgtype.TimestamptzOID,
+			Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+		})
+		return nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -15,6 +15,32 @@ type Board struct {
 	CreatedBy   int32
 	CreatedAt   pgtype.Timestamptz
 	UpdatedAt   pgtype.Timestamptz
+	WorkspaceID pgtype.Int4
+	Visibility  string
+	ArchivedAt  pgtype.Timestamptz
+	DeletedAt   pgtype.Timestamptz
+	DeletedBy   pgtype.Int4
+}
+
+type BoardInvitation struct {
+	ID          int32
+	BoardID     int32
+	Email       string
+	Role        string
+	TokenHash   string
+	InvitedBy   int32
+	InviteeID   pgtype.Int4
+	Status      string
+	ExpiresAt   pgtype.Timestamptz
+	RespondedAt pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+}
+
+type BoardMember struct {
+	BoardID   int32
+	UserID    int32
+	Role      string
+	CreatedAt pgtype.Timestamptz
 }
 
 type Card struct {
@@ -22,30 +48,179 @@ type Card struct {
 	Title       string
 	Description pgtype.Text
 	ListID      int32
-	Position    int32
 	CreatedAt   pgtype.Timestamptz
 	UpdatedAt   pgtype.Timestamptz
+	Rank        string
+	ArchivedAt  pgtype.Timestamptz
+	DeletedAt   pgtype.Timestamptz
+	DeletedBy   pgtype.Int4
+	StartAt     pgtype.Timestamptz
+	DueAt       pgtype.Timestamptz
+	Completed   bool
+	CompletedAt pgtype.Timestamptz
 }
 
-type List struct {
+type CardAssignee struct {
+	CardID     int32
+	UserID     int32
+	AssignedBy pgtype.Int4
+	CreatedAt  pgtype.Timestamptz
+}
+
+type CardLabel struct {
+	CardID    int32
+	LabelID   int32
+	CreatedAt pgtype.Timestamptz
+}
+
+type CardReminder struct {
+	ID            int32
+	CardID        int32
+	OffsetSeconds int32
+	SentForDueAt  pgtype.Timestamptz
+	CreatedBy     pgtype.Int4
+	CreatedAt     pgtype.Timestamptz
+}
+
+type EmailVerificationToken struct {
+	ID        int32
+	UserID    int32
+	Email     string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type Label struct {
 	ID        int32
-	Name      string
 	BoardID   int32
-	Position  int32
+	Name      string
+	Color     string
 	CreatedAt pgtype.Timestamptz
 	UpdatedAt pgtype.Timestamptz
 }
 
+type List struct {
+	ID         int32
+	Name       string
+	BoardID    int32
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	Rank       string
+	ArchivedAt pgtype.Timestamptz
+	DeletedAt  pgtype.Timestamptz
+	DeletedBy  pgtype.Int4
+}
+
+type LoginAttempt struct {
+	Key           string
+	Failures      int32
+	LastFailureAt pgtype.Timestamptz
+	ThrottledAt   pgtype.Timestamptz
+}
+
+type Notification struct {
+	ID        int32
+	UserID    int32
+	CardID    pgtype.Int4
+	Kind      string
+	Message   string
+	ReadAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type PasswordResetToken struct {
+	ID        int32
+	UserID    int32
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type PersonalAccessToken struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type SecurityEvent struct {
+	ID        int64
+	UserID    pgtype.Int4
+	EventType string
+	Email     string
+	IpAddress string
+	UserAgent string
+	Details   string
+	CreatedAt pgtype.Timestamptz
+}
+
 type Session struct {
 	Token  string
 	Data   []byte
 	Expiry pgtype.Timestamptz
 }
 
+type TotpRecoveryCode struct {
+	ID        int32
+	UserID    int32
+	CodeHash  string
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
 type User struct {
-	ID             int32
-	Name           string
-	Email          string
-	HashedPassword string
-	CreatedAt      pgtype.Timestamptz
+	ID              int32
+	Name            string
+	Email           string
+	HashedPassword  string
+	CreatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	TotpSecret      pgtype.Text
+	TotpEnabledAt   pgtype.Timestamptz
+	TotpLastStep    pgtype.Int8
+	HasPassword     bool
+}
+
+type UserIdentity struct {
+	ID          int32
+	UserID      int32
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   pgtype.Timestamptz
+	LastLoginAt pgtype.Timestamptz
+}
+
+type UserSession struct {
+	ID         int32
+	UserID     int32
+	Token      string
+	IpAddress  string
+	UserAgent  string
+	Device     string
+	CreatedAt  pgtype.Timestamptz
+	LastSeenAt pgtype.Timestamptz
+}
+
+type Workspace struct {
+	ID          int32
+	Name        string
+	Description pgtype.Text
+	CreatedBy   int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int32
+	UserID      int32
+	Role        string
+	CreatedAt   pgtype.Timestamptz
 }
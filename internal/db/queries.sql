@@ -2,9 +2,10 @@
 INSERT INTO users (
   name,
   email,
-  hashed_password
+  hashed_password,
+  has_password
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
 RETURNING *;
 
@@ -16,6 +17,20 @@ WHERE email = $1 LIMIT 1;
 SELECT * FROM users
 WHERE id = $1 LIMIT 1;
 
+-- name: UpdateUserProfile :one
+-- Changing the email clears its verification
+UPDATE users
+SET name = sqlc.arg(name),
+    email = sqlc.arg(email),
+    email_verified_at = CASE WHEN email = sqlc.arg(email) THEN email_verified_at ELSE NULL END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $1, has_password = TRUE
+WHERE id = $2;
+
 -- ================================
 -- BOARD QUERIES
 -- ================================
@@ -24,9 +39,11 @@ WHERE id = $1 LIMIT 1;
 INSERT INTO boards (
   name,
   description,
-  created_by
+  created_by,
+  workspace_id,
+  visibility
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -35,9 +52,18 @@ SELECT * FROM boards
 WHERE id = $1 LIMIT 1;
 
 -- name: GetBoardsByUser :many
-SELECT * FROM boards
-WHERE created_by = $1
-ORDER BY created_at DESC;
+SELECT b.* FROM boards b
+WHERE (EXISTS (
+  SELECT 1 FROM board_members m
+  WHERE m.board_id = b.id AND m.user_id = sqlc.arg(user_id)
+) OR EXISTS (
+  SELECT 1 FROM workspace_members wm
+  WHERE wm.workspace_id = b.workspace_id AND wm.user_id = sqlc.arg(user_id)
+    AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+))
+  AND b.deleted_at IS NULL
+  AND (b.archived_at IS NOT NULL) = sqlc.arg(archived)::bool
+ORDER BY b.created_at DESC;
 
 -- name: UpdateBoard :one
 UPDATE boards
@@ -45,9 +71,23 @@ SET name = $1, description = $2, updated_at = NOW()
 WHERE id = $3
 RETURNING *;
 
--- name: DeleteBoard :exec
-DELETE FROM boards
-WHERE id = $1;
+-- name: UpdateBoardWorkspace :one
+UPDATE boards
+SET workspace_id = $1, visibility = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: SetBoardArchived :one
+UPDATE boards
+SET archived_at = CASE WHEN sqlc.arg(archived)::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteBoard :execrows
+-- Moves an archived board to the trash of the user deleting it
+UPDATE boards
+SET deleted_at = NOW(), deleted_by = sqlc.arg(deleted_by)
+WHERE id = sqlc.arg(id) AND archived_at IS NOT NULL AND deleted_at IS NULL;
 
 -- ================================
 -- LIST QUERIES
@@ -57,7 +97,7 @@ WHERE id = $1;
 INSERT INTO lists (
   name,
   board_id,
-  position
+  rank
 ) VALUES (
   $1, $2, $3
 )
@@ -65,8 +105,8 @@ RETURNING *;
 
 -- name: GetListsByBoard :many
 SELECT * FROM lists
-WHERE board_id = $1
-ORDER BY position ASC;
+WHERE board_id = $1 AND deleted_at IS NULL AND (archived_at IS NOT NULL) = sqlc.arg(archived)::bool
+ORDER BY rank ASC;
 
 -- name: GetListByID :one
 SELECT * FROM lists
@@ -74,13 +114,63 @@ WHERE id = $1 LIMIT 1;
 
 -- name: UpdateList :one
 UPDATE lists
-SET name = $1, position = $2, updated_at = NOW()
-where id = $3
+SET name = $1, updated_at = NOW()
+where id = $2
 RETURNING *;
 
--- name: DeleteList :exec
-DELETE FROM lists
-where id = $1;
+-- name: MoveList :one
+UPDATE lists
+SET board_id = $1, rank = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: SetListArchived :one
+UPDATE lists
+SET archived_at = CASE WHEN sqlc.arg(archived)::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteList :execrows
+-- Moves an archived list to the trash of the user deleting it
+UPDATE lists
+SET deleted_at = NOW(), deleted_by = sqlc.arg(deleted_by)
+WHERE id = sqlc.arg(id) AND archived_at IS NOT NULL AND deleted_at IS NULL;
+
+-- name: GetLastListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND id <> sqlc.arg(exclude_id)
+ORDER BY rank DESC LIMIT 1;
+
+-- name: GetNextListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND rank > sqlc.arg(rank) AND id <> sqlc.arg(exclude_id)
+ORDER BY rank ASC LIMIT 1;
+
+-- name: GetPrevListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND rank < sqlc.arg(rank) AND id <> sqlc.arg(exclude_id)
+ORDER BY rank DESC LIMIT 1;
+
+-- name: SetListRanks :exec
+UPDATE lists
+SET rank = r.rank
+FROM (SELECT unnest(sqlc.arg(ids)::int[]) AS id, unnest(sqlc.arg(ranks)::text[]) AS rank) r
+WHERE lists.id = r.id;
+
+-- name: GetRankedListIDs :many
+SELECT id FROM lists
+WHERE board_id = $1
+ORDER BY rank ASC;
+
+-- name: GetBoardsWithLongListRanks :many
+SELECT DISTINCT board_id FROM lists
+WHERE length(rank) > sqlc.arg(max_length)::int;
+
+-- name: LockBoards :many
+SELECT id FROM boards
+WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id
+FOR UPDATE;
 
 -- ================================
 -- CARD QUERIES
@@ -91,33 +181,982 @@ INSERT INTO cards (
   title,
   description,
   list_id,
-  position
+  rank
 ) VALUES (
   $1, $2, $3, $4
 )
 RETURNING *;
 
 -- name: GetCardsByList :many
+-- An empty label_ids matches every card, otherwise cards with any of the labels
 SELECT * FROM cards
-WHERE list_id = $1
-ORDER BY position ASC;
+WHERE list_id = sqlc.arg(list_id) AND deleted_at IS NULL AND (archived_at IS NOT NULL) = sqlc.arg(archived)::bool
+  AND (COALESCE(cardinality(sqlc.arg(label_ids)::int[]), 0) = 0 OR EXISTS (
+    SELECT 1 FROM card_labels cl WHERE cl.card_id = cards.id AND cl.label_id = ANY(sqlc.arg(label_ids)::int[])
+  ))
+ORDER BY rank ASC;
+
+-- name: GetCardsByBoard :many
+-- An empty label_ids matches every card, otherwise cards with any of the labels
+SELECT c.* FROM cards c
+JOIN lists l ON l.id = c.list_id
+WHERE l.board_id = sqlc.arg(board_id) AND l.archived_at IS NULL AND c.archived_at IS NULL
+  AND l.deleted_at IS NULL AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(label_ids)::int[]), 0) = 0 OR EXISTS (
+    SELECT 1 FROM card_labels cl WHERE cl.card_id = c.id AND cl.label_id = ANY(sqlc.arg(label_ids)::int[])
+  ))
+ORDER BY c.list_id, c.rank;
 
 -- name: GetCardByID :one
 SELECT * FROM cards
 WHERE id = $1 LIMIT 1;
 
 -- name: UpdateCard :one
+-- Dates and completion only change when their set_ flag is true; completing a card stamps completed_at once
 UPDATE cards
-SET title = $1, description = $2, updated_at = NOW()
-WHERE id = $3
+SET title = sqlc.arg(title), description = sqlc.arg(description),
+  start_at = CASE WHEN sqlc.arg(set_start_at)::bool THEN sqlc.narg(start_at) ELSE start_at END,
+  due_at = CASE WHEN sqlc.arg(set_due_at)::bool THEN sqlc.narg(due_at) ELSE due_at END,
+  completed = CASE WHEN sqlc.arg(set_completed)::bool THEN sqlc.arg(completed)::bool ELSE completed END,
+  completed_at = CASE
+    WHEN NOT sqlc.arg(set_completed)::bool THEN completed_at
+    WHEN sqlc.arg(completed)::bool THEN COALESCE(completed_at, NOW())
+  END,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
 -- name: MoveCard :one
 UPDATE cards
-SET list_id = $1, position = $2, updated_at = NOW()
+SET list_id = $1, rank = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: SetCardArchived :one
+UPDATE cards
+SET archived_at = CASE WHEN sqlc.arg(archived)::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteCard :execrows
+-- Moves an archived card to the trash of the user deleting it
+UPDATE cards
+SET deleted_at = NOW(), deleted_by = sqlc.arg(deleted_by)
+WHERE id = sqlc.arg(id) AND archived_at IS NOT NULL AND deleted_at IS NULL;
+
+-- name: GetCardForUpdate :one
+SELECT * FROM cards
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: GetLastCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND id <> sqlc.arg(exclude_id)
+ORDER BY rank DESC LIMIT 1;
+
+-- name: GetNextCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND rank > sqlc.arg(rank) AND id <> sqlc.arg(exclude_id)
+ORDER BY rank ASC LIMIT 1;
+
+-- name: GetPrevCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND rank < sqlc.arg(rank) AND id <> sqlc.arg(exclude_id)
+ORDER BY rank DESC LIMIT 1;
+
+-- name: SetCardRanks :exec
+UPDATE cards
+SET rank = r.rank
+FROM (SELECT unnest(sqlc.arg(ids)::int[]) AS id, unnest(sqlc.arg(ranks)::text[]) AS rank) r
+WHERE cards.id = r.id;
+
+-- name: GetRankedCardIDs :many
+SELECT id FROM cards
+WHERE list_id = $1
+ORDER BY rank ASC;
+
+-- name: GetListsWithLongCardRanks :many
+SELECT DISTINCT list_id FROM cards
+WHERE length(rank) > sqlc.arg(max_length)::int;
+
+-- name: LockLists :many
+SELECT id FROM lists
+WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id
+FOR UPDATE;
+
+-- ================================
+-- ACCESS QUERIES
+-- ================================
+
+-- name: GetBoardAccess :one
+-- Deleted boards are only found when include_deleted is set
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM boards b
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = sqlc.arg(user_id)
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = sqlc.arg(user_id)
+WHERE b.id = sqlc.arg(id) AND (b.deleted_at IS NULL OR sqlc.arg(include_deleted)::bool) LIMIT 1;
+
+-- name: GetListAccess :one
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM lists l
+JOIN boards b ON b.id = l.board_id
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = $2
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE l.id = $1 AND l.deleted_at IS NULL AND b.deleted_at IS NULL LIMIT 1;
+
+-- name: GetCardAccess :one
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = $2
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE c.id = $1 AND c.deleted_at IS NULL AND l.deleted_at IS NULL AND b.deleted_at IS NULL LIMIT 1;
+
+-- ================================
+-- LABEL QUERIES
+-- ================================
+
+-- name: CreateLabel :one
+INSERT INTO labels (
+  board_id,
+  name,
+  color
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetLabelsByBoard :many
+SELECT * FROM labels
+WHERE board_id = $1
+ORDER BY name ASC;
+
+-- name: GetLabelByID :one
+SELECT * FROM labels
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateLabel :one
+UPDATE labels
+SET name = $1, color = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: DeleteLabel :exec
+DELETE FROM labels
+WHERE id = $1;
+
+-- name: AttachCardLabel :exec
+INSERT INTO card_labels (
+  card_id,
+  label_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachCardLabel :exec
+DELETE FROM card_labels
+WHERE card_id = $1 AND label_id = $2;
+
+-- name: GetCardLabelsByList :many
+SELECT cl.card_id, sqlc.embed(lb) FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+JOIN cards c ON c.id = cl.card_id
+WHERE c.list_id = $1
+ORDER BY lb.name ASC;
+
+-- name: GetCardLabelsByBoard :many
+SELECT cl.card_id, sqlc.embed(lb) FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+WHERE lb.board_id = $1
+ORDER BY lb.name ASC;
+
+-- name: GetCardLabels :many
+SELECT lb.* FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+WHERE cl.card_id = $1
+ORDER BY lb.name ASC;
+
+-- name: DetachForeignLabels :exec
+-- Labels belong to a board, so cards in a list that moved boards lose the labels of the old board
+DELETE FROM card_labels cl
+USING cards c, lists l, labels lb
+WHERE cl.card_id = c.id AND c.list_id = l.id AND lb.id = cl.label_id
+  AND l.id = $1 AND lb.board_id <> l.board_id;
+
+-- name: GetDueCards :many
+-- Open cards due in [due_after, due_before) on boards the user can see, soonest first.
+-- A null due_after has no lower bound.
+SELECT l.board_id, b.name AS board_name, l.name AS list_name, sqlc.embed(c) FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.due_at < sqlc.arg(due_before)
+  AND (sqlc.narg(due_after)::timestamptz IS NULL OR c.due_at >= sqlc.narg(due_after))
+  AND NOT c.completed
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+  AND (EXISTS (
+    SELECT 1 FROM board_members m
+    WHERE m.board_id = b.id AND m.user_id = sqlc.arg(user_id)
+  ) OR EXISTS (
+    SELECT 1 FROM workspace_members wm
+    WHERE wm.workspace_id = b.workspace_id AND wm.user_id = sqlc.arg(user_id)
+      AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+  ))
+ORDER BY c.due_at, c.id;
+
+-- ================================
+-- CARD ASSIGNEE QUERIES
+-- ================================
+
+-- name: AssignCard :exec
+INSERT INTO card_assignees (
+  card_id,
+  user_id,
+  assigned_by
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnassignCard :exec
+DELETE FROM card_assignees
+WHERE card_id = $1 AND user_id = $2;
+
+-- name: GetCardAssignees :many
+SELECT a.card_id, a.user_id, a.assigned_by, a.created_at, u.name, u.email FROM card_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.card_id = $1
+ORDER BY a.created_at ASC;
+
+-- name: GetCardAssigneesByBoard :many
+SELECT a.card_id, a.user_id, a.assigned_by, a.created_at, u.name, u.email FROM card_assignees a
+JOIN users u ON u.id = a.user_id
+JOIN cards c ON c.id = a.card_id
+JOIN lists l ON l.id = c.list_id
+WHERE l.board_id = $1
+ORDER BY a.created_at ASC;
+
+-- name: GetAssignedCards :many
+-- Active cards assigned to the user, ordered by board, then list and card rank
+SELECT l.board_id, b.name AS board_name, l.name AS list_name, sqlc.embed(c) FROM card_assignees a
+JOIN cards c ON c.id = a.card_id
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE a.user_id = $1
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+ORDER BY b.name, b.id, l.rank, c.rank;
+
+-- name: UnassignUsersWithoutAccess :exec
+-- Assignees must be able to see the card's board, like in GetBoardsByUser
+DELETE FROM card_assignees a
+USING cards c, lists l, boards b
+WHERE a.card_id = c.id AND c.list_id = l.id AND b.id = l.board_id
+  AND b.id = ANY(sqlc.arg(board_ids)::int[])
+  AND NOT EXISTS (
+    SELECT 1 FROM board_members m
+    WHERE m.board_id = b.id AND m.user_id = a.user_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM workspace_members wm
+    WHERE wm.workspace_id = b.workspace_id AND wm.user_id = a.user_id
+      AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+  );
+
+-- name: GetWorkspaceBoardIDs :many
+SELECT id FROM boards
+WHERE workspace_id = $1;
+
+-- ================================
+-- BOARD MEMBER QUERIES
+-- ================================
+
+-- name: CreateBoardMember :one
+INSERT INTO board_members (
+  board_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetBoardMember :one
+SELECT * FROM board_members
+WHERE board_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetBoardMembers :many
+SELECT m.board_id, m.user_id, m.role, m.created_at, u.name, u.email FROM board_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.board_id = $1
+ORDER BY m.created_at ASC;
+
+-- name: UpdateBoardMemberRole :one
+UPDATE board_members
+SET role = $1
+WHERE board_id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: DeleteBoardMember :exec
+DELETE FROM board_members
+WHERE board_id = $1 AND user_id = $2;
+
+-- name: EnsureBoardMember :exec
+INSERT INTO board_members (
+  board_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (board_id, user_id) DO NOTHING;
+
+-- ================================
+-- INVITATION QUERIES
+-- ================================
+
+-- name: CreateBoardInvitation :one
+INSERT INTO board_invitations (
+  board_id,
+  email,
+  role,
+  token_hash,
+  invited_by,
+  invitee_id,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: RevokePendingInvitations :exec
+UPDATE board_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE board_id = $1 AND LOWER(email) = LOWER(sqlc.arg(email)) AND status = 'pending';
+
+-- name: GetBoardInvitations :many
+SELECT id, board_id, email, role, invited_by, status, expires_at, responded_at, created_at FROM board_invitations
+WHERE board_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetInvitationByID :one
+SELECT * FROM board_invitations
+WHERE id = $1 LIMIT 1;
+
+-- name: GetInvitationByTokenHash :one
+SELECT * FROM board_invitations
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: GetPendingInvitationsForUser :many
+SELECT i.id, i.board_id, b.name AS board_name, i.role, i.invited_by, i.expires_at, i.created_at FROM board_invitations i
+JOIN boards b ON b.id = i.board_id
+WHERE i.invitee_id = $1 AND i.status = 'pending' AND i.expires_at > NOW() AND b.deleted_at IS NULL
+ORDER BY i.created_at DESC;
+
+-- name: RevokeBoardInvitation :one
+UPDATE board_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE id = $1 AND board_id = $2 AND status = 'pending'
+RETURNING *;
+
+-- name: RespondToInvitation :one
+UPDATE board_invitations
+SET status = $1, invitee_id = $2, responded_at = NOW()
+WHERE id = $3 AND status = 'pending'
+RETURNING *;
+
+-- name: AttachInvitationsToUser :exec
+UPDATE board_invitations
+SET invitee_id = $1
+WHERE LOWER(email) = LOWER(sqlc.arg(email)) AND status = 'pending' AND invitee_id IS NULL;
+
+-- ================================
+-- WORKSPACE QUERIES
+-- ================================
+
+-- name: CreateWorkspace :one
+INSERT INTO workspaces (
+  name,
+  description,
+  created_by
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWorkspaceByID :one
+SELECT * FROM workspaces
+WHERE id = $1 LIMIT 1;
+
+-- name: GetWorkspacesByUser :many
+SELECT w.id, w.name, w.description, w.created_by, w.created_at, w.updated_at, m.role FROM workspaces w
+JOIN workspace_members m ON m.workspace_id = w.id
+WHERE m.user_id = $1
+ORDER BY w.created_at DESC;
+
+-- name: UpdateWorkspace :one
+UPDATE workspaces
+SET name = $1, description = $2, updated_at = NOW()
 WHERE id = $3
 RETURNING *;
 
--- name: DeleteCard :exec
+-- name: DeleteWorkspace :exec
+DELETE FROM workspaces
+WHERE id = $1;
+
+-- name: GetWorkspaceAccess :one
+SELECT w.id, COALESCE(m.role, '')::text AS role FROM workspaces w
+LEFT JOIN workspace_members m ON m.workspace_id = w.id AND m.user_id = $2
+WHERE w.id = $1 LIMIT 1;
+
+-- name: GetWorkspaceBoards :many
+SELECT b.* FROM boards b
+JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE b.workspace_id = $1 AND b.archived_at IS NULL AND b.deleted_at IS NULL
+  AND (
+    wm.role IN ('owner', 'admin')
+    OR b.visibility = 'workspace'
+    OR EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = $2)
+  )
+ORDER BY b.created_at DESC;
+
+-- ================================
+-- WORKSPACE MEMBER QUERIES
+-- ================================
+
+-- name: CreateWorkspaceMember :one
+INSERT INTO workspace_members (
+  workspace_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWorkspaceMember :one
+SELECT * FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetWorkspaceMembers :many
+SELECT m.workspace_id, m.user_id, m.role, m.created_at, u.name, u.email FROM workspace_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.workspace_id = $1
+ORDER BY m.created_at ASC;
+
+-- name: UpdateWorkspaceMemberRole :one
+UPDATE workspace_members
+SET role = $1
+WHERE workspace_id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: DeleteWorkspaceMember :exec
+DELETE FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2;
+
+-- ================================
+-- SESSION QUERIES
+-- ================================
+
+-- name: TrackUserSession :exec
+-- Throttled to once a minute per session: a session seen within the last minute is
+-- only read, so busy clients neither lock the row nor use up ids from the sequence.
+INSERT INTO user_sessions (
+  user_id,
+  token,
+  ip_address,
+  user_agent,
+  device
+)
+SELECT sqlc.arg(user_id)::int, sqlc.arg(token)::text, sqlc.arg(ip_address)::text,
+  sqlc.arg(user_agent)::text, sqlc.arg(device)::text
+WHERE NOT EXISTS (
+  SELECT 1 FROM user_sessions
+  WHERE token = sqlc.arg(token) AND last_seen_at >= NOW() - INTERVAL '1 minute'
+)
+ON CONFLICT (token) DO UPDATE
+SET ip_address = EXCLUDED.ip_address, last_seen_at = NOW()
+WHERE user_sessions.last_seen_at < NOW() - INTERVAL '1 minute';
+
+-- name: GetUserSessions :many
+SELECT us.id, us.ip_address, us.user_agent, us.device, us.created_at, us.last_seen_at, s.expiry,
+  (us.token = sqlc.arg(current_token))::boolean AS current
+FROM user_sessions us
+JOIN sessions s ON s.token = us.token
+WHERE us.user_id = $1 AND s.expiry > NOW()
+ORDER BY us.last_seen_at DESC;
+
+-- name: DeleteUserSession :one
+DELETE FROM user_sessions
+WHERE id = $1 AND user_id = $2
+RETURNING token;
+
+-- name: DeleteUserSessionByToken :exec
+DELETE FROM user_sessions
+WHERE token = $1;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token = $1;
+
+-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE token IN (
+  SELECT us.token FROM user_sessions us
+  WHERE us.user_id = $1 AND us.token <> $2
+);
+
+-- name: DeleteOtherUserSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1 AND token <> $2;
+
+-- ================================
+-- PASSWORD RESET QUERIES
+-- ================================
+
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: ConsumePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id;
+
+-- name: InvalidatePasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- ================================
+-- EMAIL VERIFICATION QUERIES
+-- ================================
+
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (
+  user_id,
+  email,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: ConsumeEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id, email;
+
+-- name: InvalidateEmailVerificationTokens :exec
+UPDATE email_verification_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: MarkEmailVerified :one
+UPDATE users
+SET email_verified_at = NOW()
+WHERE id = $1 AND email = $2
+RETURNING *;
+
+-- ================================
+-- PERSONAL ACCESS TOKEN QUERIES
+-- ================================
+
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+  user_id,
+  name,
+  token_hash,
+  scopes,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetPersonalAccessTokens :many
+SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT * FROM personal_access_tokens
+WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: TouchPersonalAccessToken :exec
+-- Throttled to once a minute so busy scripts don't write on every request
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: DeletePersonalAccessToken :execrows
+DELETE FROM personal_access_tokens
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteUserPersonalAccessTokens :exec
+DELETE FROM personal_access_tokens
+WHERE user_id = $1;
+
+-- ================================
+-- TWO-FACTOR AUTHENTICATION QUERIES
+-- ================================
+
+-- name: SetPendingTOTPSecret :exec
+-- Enrollment is only confirmed once the user proves they can generate codes
+UPDATE users
+SET totp_secret = $2, totp_enabled_at = NULL, totp_last_step = NULL
+WHERE id = $1 AND totp_enabled_at IS NULL;
+
+-- name: EnableTOTP :execrows
+UPDATE users
+SET totp_enabled_at = NOW(), totp_last_step = $2
+WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL;
+
+-- name: DisableTOTP :exec
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL
+WHERE id = $1;
+
+-- name: UseTOTPStep :execrows
+-- Only one login per time step, so an intercepted code cannot be replayed
+UPDATE users
+SET totp_last_step = $2
+WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2);
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (
+  user_id,
+  code_hash
+) VALUES (
+  $1, $2
+);
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1;
+
+-- ================================
+-- SINGLE SIGN-ON QUERIES
+-- ================================
+
+-- name: GetUserByIdentity :one
+SELECT u.* FROM users u
+JOIN user_identities i ON i.user_id = u.id
+WHERE i.provider = $1 AND i.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (
+  user_id,
+  provider,
+  subject,
+  email
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = NOW(), email = $3
+WHERE provider = $1 AND subject = $2;
+
+-- ================================
+-- LOGIN GUARD QUERIES
+-- ================================
+
+-- name: RecordLoginFailure :one
+-- Failures older than the window no longer count, so the streak starts again
+INSERT INTO login_attempts (key, failures, last_failure_at)
+VALUES (sqlc.arg(key), 1, NOW())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+      WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => sqlc.arg(window_seconds)::float8) THEN 1
+      ELSE login_attempts.failures + 1
+    END,
+    last_failure_at = NOW()
+RETURNING failures, last_failure_at;
+
+-- name: LockLoginAttempts :one
+-- Creates the streak if it is missing and locks it for the rest of the transaction,
+-- so even the first attempts on a key are checked one after another
+INSERT INTO login_attempts (key, failures, last_failure_at)
+VALUES ($1, 0, NOW())
+ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+RETURNING failures, last_failure_at;
+
+-- name: ReleaseLoginAttempt :exec
+UPDATE login_attempts SET failures = failures - 1
+WHERE key = $1 AND failures > 0;
+
+-- name: MarkLoginThrottled :execrows
+-- Only the first throttled attempt after each failure marks the streak
+UPDATE login_attempts SET throttled_at = NOW()
+WHERE key = $1 AND (throttled_at IS NULL OR throttled_at < last_failure_at);
+
+-- name: DeleteLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE key = $1;
+
+-- name: DeleteStaleLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE last_failure_at < $1;
+
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (
+  user_id,
+  event_type,
+  email,
+  ip_address,
+  user_agent,
+  details
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- ================================
+-- REMINDER QUERIES
+-- ================================
+
+-- name: CreateCardReminder :one
+INSERT INTO card_reminders (
+  card_id,
+  offset_seconds,
+  created_by
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetCardReminders :many
+SELECT * FROM card_reminders
+WHERE card_id = $1
+ORDER BY offset_seconds DESC;
+
+-- name: DeleteCardReminder :execrows
+DELETE FROM card_reminders
+WHERE id = $1 AND card_id = $2;
+
+-- name: LockDueReminder :one
+-- Claims one reminder that should fire now: its card is open, due in the future and
+-- within the offset, and it has not fired for this due date. Other instances skip it.
+SELECT r.id, r.card_id, c.title AS card_title, c.due_at, l.board_id, b.name AS board_name FROM card_reminders r
+JOIN cards c ON c.id = r.card_id
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.due_at > NOW()
+  AND c.due_at - r.offset_seconds * INTERVAL '1 second' <= NOW()
+  AND r.sent_for_due_at IS DISTINCT FROM c.due_at
+  AND NOT c.completed
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+ORDER BY c.due_at
+LIMIT 1
+FOR UPDATE OF r SKIP LOCKED;
+
+-- name: MarkReminderSent :exec
+UPDATE card_reminders
+SET sent_for_due_at = $1
+WHERE id = $2;
+
+-- ================================
+-- NOTIFICATION QUERIES
+-- ================================
+
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  user_id,
+  card_id,
+  kind,
+  message
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetUserNotifications :many
+SELECT * FROM notifications
+WHERE user_id = $1 AND (read_at IS NULL OR NOT sqlc.arg(unread_only)::bool)
+ORDER BY created_at DESC
+LIMIT 100;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- ================================
+-- TRASH QUERIES
+-- ================================
+
+-- name: GetTrashedBoards :many
+SELECT * FROM boards
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetTrashedLists :many
+SELECT * FROM lists
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetTrashedCards :many
+SELECT * FROM cards
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetTrashedList :one
+-- A list in the user's trash, and whether its board is in a trash too
+SELECT l.board_id, (b.deleted_at IS NOT NULL)::bool AS board_deleted FROM lists l
+JOIN boards b ON b.id = l.board_id
+WHERE l.id = sqlc.arg(id) AND l.deleted_by = sqlc.arg(user_id) AND l.deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: GetTrashedCard :one
+-- A card in the user's trash, and whether its list or board is in a trash too
+SELECT l.board_id, (l.deleted_at IS NOT NULL OR b.deleted_at IS NOT NULL)::bool AS parent_deleted FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.id = sqlc.arg(id) AND c.deleted_by = sqlc.arg(user_id) AND c.deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: RestoreBoard :one
+UPDATE boards
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_by = sqlc.arg(user_id) AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreList :one
+UPDATE lists
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_by = sqlc.arg(user_id) AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreCard :one
+UPDATE cards
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_by = sqlc.arg(user_id) AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedBoards :execrows
+DELETE FROM boards
+WHERE deleted_at < $1;
+
+-- name: PurgeDeletedLists :execrows
+DELETE FROM lists
+WHERE deleted_at < $1;
+
+-- name: PurgeDeletedCards :execrows
 DELETE FROM cards
-WHERE id = $1;
\ No newline at end of file
+WHERE deleted_at < $1;
+
+-- ================================
+-- ACCOUNT EXPORT AND DELETION QUERIES
+-- ================================
+
+-- name: GetExportBoards :many
+SELECT b.id, b.name, b.description, b.created_by, b.workspace_id, b.visibility, b.created_at, b.updated_at, b.archived_at, m.role FROM boards b
+JOIN board_members m ON m.board_id = b.id
+WHERE m.user_id = $1
+ORDER BY b.id;
+
+-- name: GetExportLists :many
+SELECT l.* FROM lists l
+JOIN board_members m ON m.board_id = l.board_id
+WHERE m.user_id = $1
+ORDER BY l.board_id, l.rank;
+
+-- name: GetExportCards :many
+SELECT c.* FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN board_members m ON m.board_id = l.board_id
+WHERE m.user_id = $1
+ORDER BY c.list_id, c.rank;
+
+-- name: GetUserIdentities :many
+SELECT id, provider, subject, email, created_at, last_login_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetUserSecurityEvents :many
+SELECT id, event_type, email, ip_address, user_agent, details, created_at FROM security_events
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetOwnedBoards :many
+-- Boards the user owns or created, with the number of other users who can reach them and could
+-- take them over: board members, workspace owners and admins, and for workspace-visible
+-- boards every workspace member
+SELECT b.id, b.name,
+  (SELECT COUNT(*) FROM users ou WHERE ou.id <> sqlc.arg(user_id) AND (
+    EXISTS (SELECT 1 FROM board_members om WHERE om.board_id = b.id AND om.user_id = ou.id)
+    OR EXISTS (
+      SELECT 1 FROM workspace_members wm
+      WHERE wm.workspace_id = b.workspace_id AND wm.user_id = ou.id
+        AND (b.visibility = 'workspace' OR wm.role IN ('owner', 'admin'))
+    )
+  ))::int AS other_members
+FROM boards b
+WHERE b.created_by = sqlc.arg(user_id)
+   OR EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = sqlc.arg(user_id) AND m.role = 'owner')
+ORDER BY b.id;
+
+-- name: GetOwnedWorkspaces :many
+-- Workspaces the user owns or created, with the number of other members who could take them over
+SELECT w.id, w.name,
+  (SELECT COUNT(*) FROM workspace_members om WHERE om.workspace_id = w.id AND om.user_id <> sqlc.arg(user_id))::int AS other_members
+FROM workspaces w
+WHERE w.created_by = sqlc.arg(user_id)
+   OR EXISTS (SELECT 1 FROM workspace_members m WHERE m.workspace_id = w.id AND m.user_id = sqlc.arg(user_id) AND m.role = 'owner')
+ORDER BY w.id;
+
+-- name: TransferBoardOwnership :execrows
+-- The new owner must already reach the board, as a member or through its workspace, and becomes
+-- an owning member; created_by follows so the board survives the old owner's deletion
+WITH promoted AS (
+  INSERT INTO board_members (board_id, user_id, role)
+  SELECT b.id, sqlc.arg(new_owner_id), 'owner' FROM boards b
+  WHERE b.id = sqlc.arg(board_id) AND (
+    EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = sqlc.arg(new_owner_id))
+    OR EXISTS (
+      SELECT 1 FROM workspace_members wm
+      WHERE wm.workspace_id = b.workspace_id AND wm.user_id = sqlc.arg(new_owner_id)
+        AND (b.visibility = 'workspace' OR wm.role IN ('owner', 'admin'))
+    )
+  )
+  ON CONFLICT (board_id, user_id) DO UPDATE SET role = 'owner'
+  RETURNING board_members.board_id
+)
+UPDATE boards
+SET created_by = sqlc.arg(new_owner_id)
+WHERE boards.id IN (SELECT promoted.board_id FROM promoted);
+
+-- name: TransferWorkspaceOwnership :execrows
+-- The new owner must already be a member; created_by follows so the workspace survives the old owner's deletion
+WITH promoted AS (
+  UPDATE workspace_members
+  SET role = 'owner'
+  WHERE workspace_members.workspace_id = sqlc.arg(workspace_id) AND workspace_members.user_id = sqlc.arg(new_owner_id)
+  RETURNING workspace_members.workspace_id
+)
+UPDATE workspaces
+SET created_by = sqlc.arg(new_owner_id)
+WHERE workspaces.id IN (SELECT promoted.workspace_id FROM promoted);
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
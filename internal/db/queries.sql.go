@@ -11,29 +11,134 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const assignCard = `-- name: AssignCard :exec
+
+INSERT INTO card_assignees (
+  card_id,
+  user_id,
+  assigned_by
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING
+`
+
+type AssignCardParams struct {
+	CardID     int32
+	UserID     int32
+	AssignedBy pgtype.Int4
+}
+
+// ================================
+// CARD ASSIGNEE QUERIES
+// ================================
+func (q *Queries) AssignCard(ctx context.Context, arg AssignCardParams) error {
+	_, err := q.db.Exec(ctx, assignCard, arg.CardID, arg.UserID, arg.AssignedBy)
+	return err
+}
+
+const attachCardLabel = `-- name: AttachCardLabel :exec
+INSERT INTO card_labels (
+  card_id,
+  label_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type AttachCardLabelParams struct {
+	CardID  int32
+	LabelID int32
+}
+
+func (q *Queries) AttachCardLabel(ctx context.Context, arg AttachCardLabelParams) error {
+	_, err := q.db.Exec(ctx, attachCardLabel, arg.CardID, arg.LabelID)
+	return err
+}
+
+const attachInvitationsToUser = `-- name: AttachInvitationsToUser :exec
+UPDATE board_invitations
+SET invitee_id = $1
+WHERE LOWER(email) = LOWER($2) AND status = 'pending' AND invitee_id IS NULL
+`
+
+type AttachInvitationsToUserParams struct {
+	InviteeID pgtype.Int4
+	Email     string
+}
+
+func (q *Queries) AttachInvitationsToUser(ctx context.Context, arg AttachInvitationsToUserParams) error {
+	_, err := q.db.Exec(ctx, attachInvitationsToUser, arg.InviteeID, arg.Email)
+	return err
+}
+
+const consumeEmailVerificationToken = `-- name: ConsumeEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id, email
+`
+
+type ConsumeEmailVerificationTokenRow struct {
+	UserID int32
+	Email  string
+}
+
+func (q *Queries) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (ConsumeEmailVerificationTokenRow, error) {
+	row := q.db.QueryRow(ctx, consumeEmailVerificationToken, tokenHash)
+	var i ConsumeEmailVerificationTokenRow
+	err := row.Scan(&i.UserID, &i.Email)
+	return i, err
+}
+
+const consumePasswordResetToken = `-- name: ConsumePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id
+`
+
+func (q *Queries) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (int32, error) {
+	row := q.db.QueryRow(ctx, consumePasswordResetToken, tokenHash)
+	var user_id int32
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
 const createBoard = `-- name: CreateBoard :one
 
 INSERT INTO boards (
   name,
   description,
-  created_by
+  created_by,
+  workspace_id,
+  visibility
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
-RETURNING id, name, description, created_by, created_at, updated_at
+RETURNING id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by
 `
 
 type CreateBoardParams struct {
 	Name        string
 	Description pgtype.Text
 	CreatedBy   int32
+	WorkspaceID pgtype.Int4
+	Visibility  string
 }
 
 // ================================
 // BOARD QUERIES
 // ================================
 func (q *Queries) CreateBoard(ctx context.Context, arg CreateBoardParams) (Board, error) {
-	row := q.db.QueryRow(ctx, createBoard, arg.Name, arg.Description, arg.CreatedBy)
+	row := q.db.QueryRow(ctx, createBoard,
+		arg.Name,
+		arg.Description,
+		arg.CreatedBy,
+		arg.WorkspaceID,
+		arg.Visibility,
+	)
 	var i Board
 	err := row.Scan(
 		&i.ID,
@@ -42,6 +147,100 @@ func (q *Queries) CreateBoard(ctx context.Context, arg CreateBoardParams) (Board
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const createBoardInvitation = `-- name: CreateBoardInvitation :one
+
+INSERT INTO board_invitations (
+  board_id,
+  email,
+  role,
+  token_hash,
+  invited_by,
+  invitee_id,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, board_id, email, role, token_hash, invited_by, invitee_id, status, expires_at, responded_at, created_at
+`
+
+type CreateBoardInvitationParams struct {
+	BoardID   int32
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy int32
+	InviteeID pgtype.Int4
+	ExpiresAt pgtype.Timestamptz
+}
+
+// ================================
+// INVITATION QUERIES
+// ================================
+func (q *Queries) CreateBoardInvitation(ctx context.Context, arg CreateBoardInvitationParams) (BoardInvitation, error) {
+	row := q.db.QueryRow(ctx, createBoardInvitation,
+		arg.BoardID,
+		arg.Email,
+		arg.Role,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.InviteeID,
+		arg.ExpiresAt,
+	)
+	var i BoardInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.InviteeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createBoardMember = `-- name: CreateBoardMember :one
+
+INSERT INTO board_members (
+  board_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING board_id, user_id, role, created_at
+`
+
+type CreateBoardMemberParams struct {
+	BoardID int32
+	UserID  int32
+	Role    string
+}
+
+// ================================
+// BOARD MEMBER QUERIES
+// ================================
+func (q *Queries) CreateBoardMember(ctx context.Context, arg CreateBoardMemberParams) (BoardMember, error) {
+	row := q.db.QueryRow(ctx, createBoardMember, arg.BoardID, arg.UserID, arg.Role)
+	var i BoardMember
+	err := row.Scan(
+		&i.BoardID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -52,18 +251,18 @@ INSERT INTO cards (
   title,
   description,
   list_id,
-  position
+  rank
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, title, description, list_id, position, created_at, updated_at
+RETURNING id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at
 `
 
 type CreateCardParams struct {
 	Title       string
 	Description pgtype.Text
 	ListID      int32
-	Position    int32
+	Rank        string
 }
 
 // ================================
@@ -74,7 +273,7 @@ func (q *Queries) CreateCard(ctx context.Context, arg CreateCardParams) (Card, e
 		arg.Title,
 		arg.Description,
 		arg.ListID,
-		arg.Position,
+		arg.Rank,
 	)
 	var i Card
 	err := row.Scan(
@@ -82,7 +281,116 @@ func (q *Queries) CreateCard(ctx context.Context, arg CreateCardParams) (Card, e
 		&i.Title,
 		&i.Description,
 		&i.ListID,
-		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createCardReminder = `-- name: CreateCardReminder :one
+
+INSERT INTO card_reminders (
+  card_id,
+  offset_seconds,
+  created_by
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, card_id, offset_seconds, sent_for_due_at, created_by, created_at
+`
+
+type CreateCardReminderParams struct {
+	CardID        int32
+	OffsetSeconds int32
+	CreatedBy     pgtype.Int4
+}
+
+// ================================
+// REMINDER QUERIES
+// ================================
+func (q *Queries) CreateCardReminder(ctx context.Context, arg CreateCardReminderParams) (CardReminder, error) {
+	row := q.db.QueryRow(ctx, createCardReminder, arg.CardID, arg.OffsetSeconds, arg.CreatedBy)
+	var i CardReminder
+	err := row.Scan(
+		&i.ID,
+		&i.CardID,
+		&i.OffsetSeconds,
+		&i.SentForDueAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createEmailVerificationToken = `-- name: CreateEmailVerificationToken :exec
+
+INSERT INTO email_verification_tokens (
+  user_id,
+  email,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+`
+
+type CreateEmailVerificationTokenParams struct {
+	UserID    int32
+	Email     string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+}
+
+// ================================
+// EMAIL VERIFICATION QUERIES
+// ================================
+func (q *Queries) CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, createEmailVerificationToken,
+		arg.UserID,
+		arg.Email,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createLabel = `-- name: CreateLabel :one
+
+INSERT INTO labels (
+  board_id,
+  name,
+  color
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, board_id, name, color, created_at, updated_at
+`
+
+type CreateLabelParams struct {
+	BoardID int32
+	Name    string
+	Color   string
+}
+
+// ================================
+// LABEL QUERIES
+// ================================
+func (q *Queries) CreateLabel(ctx context.Context, arg CreateLabelParams) (Label, error) {
+	row := q.db.QueryRow(ctx, createLabel, arg.BoardID, arg.Name, arg.Color)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Name,
+		&i.Color,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -94,55 +402,233 @@ const createList = `-- name: CreateList :one
 INSERT INTO lists (
   name,
   board_id,
-  position
+  rank
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, name, board_id, position, created_at, updated_at
+RETURNING id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by
 `
 
 type CreateListParams struct {
-	Name     string
-	BoardID  int32
-	Position int32
+	Name    string
+	BoardID int32
+	Rank    string
 }
 
 // ================================
 // LIST QUERIES
 // ================================
 func (q *Queries) CreateList(ctx context.Context, arg CreateListParams) (List, error) {
-	row := q.db.QueryRow(ctx, createList, arg.Name, arg.BoardID, arg.Position)
+	row := q.db.QueryRow(ctx, createList, arg.Name, arg.BoardID, arg.Rank)
 	var i List
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.BoardID,
-		&i.Position,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const createNotification = `-- name: CreateNotification :one
+
+INSERT INTO notifications (
+  user_id,
+  card_id,
+  kind,
+  message
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, user_id, card_id, kind, message, read_at, created_at
+`
+
+type CreateNotificationParams struct {
+	UserID  int32
+	CardID  pgtype.Int4
+	Kind    string
+	Message string
+}
+
+// ================================
+// NOTIFICATION QUERIES
+// ================================
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, createNotification,
+		arg.UserID,
+		arg.CardID,
+		arg.Kind,
+		arg.Message,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CardID,
+		&i.Kind,
+		&i.Message,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPasswordResetToken = `-- name: CreatePasswordResetToken :exec
+
+INSERT INTO password_reset_tokens (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type CreatePasswordResetTokenParams struct {
+	UserID    int32
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+}
+
+// ================================
+// PASSWORD RESET QUERIES
+// ================================
+func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error {
+	_, err := q.db.Exec(ctx, createPasswordResetToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	return err
+}
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+
+INSERT INTO personal_access_tokens (
+  user_id,
+  name,
+  token_hash,
+  scopes,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at
+`
+
+type CreatePersonalAccessTokenParams struct {
+	UserID    int32
+	Name      string
+	TokenHash string
+	Scopes    []string
+	ExpiresAt pgtype.Timestamptz
+}
+
+// ================================
+// PERSONAL ACCESS TOKEN QUERIES
+// ================================
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, createPersonalAccessToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (
+  user_id,
+  code_hash
+) VALUES (
+  $1, $2
+)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   int32
+	CodeHash string
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const createSecurityEvent = `-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (
+  user_id,
+  event_type,
+  email,
+  ip_address,
+  user_agent,
+  details
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+`
+
+type CreateSecurityEventParams struct {
+	UserID    pgtype.Int4
+	EventType string
+	Email     string
+	IpAddress string
+	UserAgent string
+	Details   string
+}
+
+func (q *Queries) CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error {
+	_, err := q.db.Exec(ctx, createSecurityEvent,
+		arg.UserID,
+		arg.EventType,
+		arg.Email,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Details,
+	)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   name,
   email,
-  hashed_password
+  hashed_password,
+  has_password
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
-RETURNING id, name, email, hashed_password, created_at
+RETURNING id, name, email, hashed_password, created_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, has_password
 `
 
 type CreateUserParams struct {
 	Name           string
 	Email          string
 	HashedPassword string
+	HasPassword    bool
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRow(ctx, createUser,
+		arg.Name,
+		arg.Email,
+		arg.HashedPassword,
+		arg.HasPassword,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -150,48 +636,67 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
 	)
 	return i, err
 }
 
-const deleteBoard = `-- name: DeleteBoard :exec
-DELETE FROM boards
-WHERE id = $1
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (
+  user_id,
+  provider,
+  subject,
+  email
+) VALUES (
+  $1, $2, $3, $4
+)
 `
 
-func (q *Queries) DeleteBoard(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteBoard, id)
-	return err
+type CreateUserIdentityParams struct {
+	UserID   int32
+	Provider string
+	Subject  string
+	Email    string
 }
 
-const deleteCard = `-- name: DeleteCard :exec
-DELETE FROM cards
-WHERE id = $1
-`
-
-func (q *Queries) DeleteCard(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteCard, id)
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.Exec(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.Subject,
+		arg.Email,
+	)
 	return err
 }
 
-const deleteList = `-- name: DeleteList :exec
-DELETE FROM lists
-where id = $1
+const createWorkspace = `-- name: CreateWorkspace :one
+
+INSERT INTO workspaces (
+  name,
+  description,
+  created_by
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, name, description, created_by, created_at, updated_at
 `
 
-func (q *Queries) DeleteList(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteList, id)
-	return err
+type CreateWorkspaceParams struct {
+	Name        string
+	Description pgtype.Text
+	CreatedBy   int32
 }
 
-const getBoardByID = `-- name: GetBoardByID :one
-SELECT id, name, description, created_by, created_at, updated_at FROM boards
-WHERE id = $1 LIMIT 1
-`
-
-func (q *Queries) GetBoardByID(ctx context.Context, id int32) (Board, error) {
-	row := q.db.QueryRow(ctx, getBoardByID, id)
-	var i Board
+// ================================
+// WORKSPACE QUERIES
+// ================================
+func (q *Queries) CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRow(ctx, createWorkspace, arg.Name, arg.Description, arg.CreatedBy)
+	var i Workspace
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
@@ -203,28 +708,433 @@ func (q *Queries) GetBoardByID(ctx context.Context, id int32) (Board, error) {
 	return i, err
 }
 
-const getBoardsByUser = `-- name: GetBoardsByUser :many
-SELECT id, name, description, created_by, created_at, updated_at FROM boards
-WHERE created_by = $1
-ORDER BY created_at DESC
+const createWorkspaceMember = `-- name: CreateWorkspaceMember :one
+
+INSERT INTO workspace_members (
+  workspace_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING workspace_id, user_id, role, created_at
 `
 
-func (q *Queries) GetBoardsByUser(ctx context.Context, createdBy int32) ([]Board, error) {
-	rows, err := q.db.Query(ctx, getBoardsByUser, createdBy)
-	if err != nil {
-		return nil, err
-	}
+type CreateWorkspaceMemberParams struct {
+	WorkspaceID int32
+	UserID      int32
+	Role        string
+}
+
+// ================================
+// WORKSPACE MEMBER QUERIES
+// ================================
+func (q *Queries) CreateWorkspaceMember(ctx context.Context, arg CreateWorkspaceMemberParams) (WorkspaceMember, error) {
+	row := q.db.QueryRow(ctx, createWorkspaceMember, arg.WorkspaceID, arg.UserID, arg.Role)
+	var i WorkspaceMember
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteBoard = `-- name: DeleteBoard :execrows
+UPDATE boards
+SET deleted_at = NOW(), deleted_by = $1
+WHERE id = $2 AND archived_at IS NOT NULL AND deleted_at IS NULL
+`
+
+type DeleteBoardParams struct {
+	DeletedBy pgtype.Int4
+	ID        int32
+}
+
+// Moves an archived board to the trash of the user deleting it
+func (q *Queries) DeleteBoard(ctx context.Context, arg DeleteBoardParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBoard, arg.DeletedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteBoardMember = `-- name: DeleteBoardMember :exec
+DELETE FROM board_members
+WHERE board_id = $1 AND user_id = $2
+`
+
+type DeleteBoardMemberParams struct {
+	BoardID int32
+	UserID  int32
+}
+
+func (q *Queries) DeleteBoardMember(ctx context.Context, arg DeleteBoardMemberParams) error {
+	_, err := q.db.Exec(ctx, deleteBoardMember, arg.BoardID, arg.UserID)
+	return err
+}
+
+const deleteCard = `-- name: DeleteCard :execrows
+UPDATE cards
+SET deleted_at = NOW(), deleted_by = $1
+WHERE id = $2 AND archived_at IS NOT NULL AND deleted_at IS NULL
+`
+
+type DeleteCardParams struct {
+	DeletedBy pgtype.Int4
+	ID        int32
+}
+
+// Moves an archived card to the trash of the user deleting it
+func (q *Queries) DeleteCard(ctx context.Context, arg DeleteCardParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCard, arg.DeletedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCardReminder = `-- name: DeleteCardReminder :execrows
+DELETE FROM card_reminders
+WHERE id = $1 AND card_id = $2
+`
+
+type DeleteCardReminderParams struct {
+	ID     int32
+	CardID int32
+}
+
+func (q *Queries) DeleteCardReminder(ctx context.Context, arg DeleteCardReminderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCardReminder, arg.ID, arg.CardID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteLabel = `-- name: DeleteLabel :exec
+DELETE FROM labels
+WHERE id = $1
+`
+
+func (q *Queries) DeleteLabel(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteLabel, id)
+	return err
+}
+
+const deleteList = `-- name: DeleteList :execrows
+UPDATE lists
+SET deleted_at = NOW(), deleted_by = $1
+WHERE id = $2 AND archived_at IS NOT NULL AND deleted_at IS NULL
+`
+
+type DeleteListParams struct {
+	DeletedBy pgtype.Int4
+	ID        int32
+}
+
+// Moves an archived list to the trash of the user deleting it
+func (q *Queries) DeleteList(ctx context.Context, arg DeleteListParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteList, arg.DeletedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteLoginAttempts = `-- name: DeleteLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE key = $1
+`
+
+func (q *Queries) DeleteLoginAttempts(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, deleteLoginAttempts, key)
+	return err
+}
+
+const deleteOtherSessions = `-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE token IN (
+  SELECT us.token FROM user_sessions us
+  WHERE us.user_id = $1 AND us.token <> $2
+)
+`
+
+type DeleteOtherSessionsParams struct {
+	UserID int32
+	Token  string
+}
+
+func (q *Queries) DeleteOtherSessions(ctx context.Context, arg DeleteOtherSessionsParams) error {
+	_, err := q.db.Exec(ctx, deleteOtherSessions, arg.UserID, arg.Token)
+	return err
+}
+
+const deleteOtherUserSessions = `-- name: DeleteOtherUserSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1 AND token <> $2
+`
+
+type DeleteOtherUserSessionsParams struct {
+	UserID int32
+	Token  string
+}
+
+func (q *Queries) DeleteOtherUserSessions(ctx context.Context, arg DeleteOtherUserSessionsParams) error {
+	_, err := q.db.Exec(ctx, deleteOtherUserSessions, arg.UserID, arg.Token)
+	return err
+}
+
+const deletePersonalAccessToken = `-- name: DeletePersonalAccessToken :execrows
+DELETE FROM personal_access_tokens
+WHERE id = $1 AND user_id = $2
+`
+
+type DeletePersonalAccessTokenParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) DeletePersonalAccessToken(ctx context.Context, arg DeletePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token = $1
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, deleteSession, token)
+	return err
+}
+
+const deleteStaleLoginAttempts = `-- name: DeleteStaleLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE last_failure_at < $1
+`
+
+func (q *Queries) DeleteStaleLoginAttempts(ctx context.Context, lastFailureAt pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteStaleLoginAttempts, lastFailureAt)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserPersonalAccessTokens = `-- name: DeleteUserPersonalAccessTokens :exec
+DELETE FROM personal_access_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserPersonalAccessTokens(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteUserPersonalAccessTokens, userID)
+	return err
+}
+
+const deleteUserSession = `-- name: DeleteUserSession :one
+DELETE FROM user_sessions
+WHERE id = $1 AND user_id = $2
+RETURNING token
+`
+
+type DeleteUserSessionParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (string, error) {
+	row := q.db.QueryRow(ctx, deleteUserSession, arg.ID, arg.UserID)
+	var token string
+	err := row.Scan(&token)
+	return token, err
+}
+
+const deleteUserSessionByToken = `-- name: DeleteUserSessionByToken :exec
+DELETE FROM user_sessions
+WHERE token = $1
+`
+
+func (q *Queries) DeleteUserSessionByToken(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, deleteUserSessionByToken, token)
+	return err
+}
+
+const deleteWorkspace = `-- name: DeleteWorkspace :exec
+DELETE FROM workspaces
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWorkspace(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteWorkspace, id)
+	return err
+}
+
+const deleteWorkspaceMember = `-- name: DeleteWorkspaceMember :exec
+DELETE FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2
+`
+
+type DeleteWorkspaceMemberParams struct {
+	WorkspaceID int32
+	UserID      int32
+}
+
+func (q *Queries) DeleteWorkspaceMember(ctx context.Context, arg DeleteWorkspaceMemberParams) error {
+	_, err := q.db.Exec(ctx, deleteWorkspaceMember, arg.WorkspaceID, arg.UserID)
+	return err
+}
+
+const detachCardLabel = `-- name: DetachCardLabel :exec
+DELETE FROM card_labels
+WHERE card_id = $1 AND label_id = $2
+`
+
+type DetachCardLabelParams struct {
+	CardID  int32
+	LabelID int32
+}
+
+func (q *Queries) DetachCardLabel(ctx context.Context, arg DetachCardLabelParams) error {
+	_, err := q.db.Exec(ctx, detachCardLabel, arg.CardID, arg.LabelID)
+	return err
+}
+
+const detachForeignLabels = `-- name: DetachForeignLabels :exec
+DELETE FROM card_labels cl
+USING cards c, lists l, labels lb
+WHERE cl.card_id = c.id AND c.list_id = l.id AND lb.id = cl.label_id
+  AND l.id = $1 AND lb.board_id <> l.board_id
+`
+
+// Labels belong to a board, so cards in a list that moved boards lose the labels of the old board
+func (q *Queries) DetachForeignLabels(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, detachForeignLabels, id)
+	return err
+}
+
+const disableTOTP = `-- name: DisableTOTP :exec
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL
+WHERE id = $1
+`
+
+func (q *Queries) DisableTOTP(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, disableTOTP, id)
+	return err
+}
+
+const enableTOTP = `-- name: EnableTOTP :execrows
+UPDATE users
+SET totp_enabled_at = NOW(), totp_last_step = $2
+WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+`
+
+type EnableTOTPParams struct {
+	ID           int32
+	TotpLastStep pgtype.Int8
+}
+
+func (q *Queries) EnableTOTP(ctx context.Context, arg EnableTOTPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enableTOTP, arg.ID, arg.TotpLastStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const ensureBoardMember = `-- name: EnsureBoardMember :exec
+INSERT INTO board_members (
+  board_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (board_id, user_id) DO NOTHING
+`
+
+type EnsureBoardMemberParams struct {
+	BoardID int32
+	UserID  int32
+	Role    string
+}
+
+func (q *Queries) EnsureBoardMember(ctx context.Context, arg EnsureBoardMemberParams) error {
+	_, err := q.db.Exec(ctx, ensureBoardMember, arg.BoardID, arg.UserID, arg.Role)
+	return err
+}
+
+const getAssignedCards = `-- name: GetAssignedCards :many
+SELECT l.board_id, b.name AS board_name, l.name AS list_name, c.id, c.title, c.description, c.list_id, c.created_at, c.updated_at, c.rank, c.archived_at, c.deleted_at, c.deleted_by, c.start_at, c.due_at, c.completed, c.completed_at FROM card_assignees a
+JOIN cards c ON c.id = a.card_id
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE a.user_id = $1
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+ORDER BY b.name, b.id, l.rank, c.rank
+`
+
+type GetAssignedCardsRow struct {
+	BoardID   int32
+	BoardName string
+	ListName  string
+	Card      Card
+}
+
+// Active cards assigned to the user, ordered by board, then list and card rank
+func (q *Queries) GetAssignedCards(ctx context.Context, userID int32) ([]GetAssignedCardsRow, error) {
+	rows, err := q.db.Query(ctx, getAssignedCards, userID)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
-	var items []Board
+	var items []GetAssignedCardsRow
 	for rows.Next() {
-		var i Board
+		var i GetAssignedCardsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedBy,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.BoardID,
+			&i.BoardName,
+			&i.ListName,
+			&i.Card.ID,
+			&i.Card.Title,
+			&i.Card.Description,
+			&i.Card.ListID,
+			&i.Card.CreatedAt,
+			&i.Card.UpdatedAt,
+			&i.Card.Rank,
+			&i.Card.ArchivedAt,
+			&i.Card.DeletedAt,
+			&i.Card.DeletedBy,
+			&i.Card.StartAt,
+			&i.Card.DueAt,
+			&i.Card.Completed,
+			&i.Card.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -236,49 +1146,104 @@ func (q *Queries) GetBoardsByUser(ctx context.Context, createdBy int32) ([]Board
 	return items, nil
 }
 
-const getCardByID = `-- name: GetCardByID :one
-SELECT id, title, description, list_id, position, created_at, updated_at FROM cards
+const getBoardAccess = `-- name: GetBoardAccess :one
+
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM boards b
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = $1
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $1
+WHERE b.id = $2 AND (b.deleted_at IS NULL OR $3::bool) LIMIT 1
+`
+
+type GetBoardAccessParams struct {
+	UserID         int32
+	ID             int32
+	IncludeDeleted bool
+}
+
+type GetBoardAccessRow struct {
+	ID            int32
+	Visibility    string
+	Role          string
+	WorkspaceRole string
+}
+
+// ================================
+// ACCESS QUERIES
+// ================================
+// Deleted boards are only found when include_deleted is set
+func (q *Queries) GetBoardAccess(ctx context.Context, arg GetBoardAccessParams) (GetBoardAccessRow, error) {
+	row := q.db.QueryRow(ctx, getBoardAccess, arg.UserID, arg.ID, arg.IncludeDeleted)
+	var i GetBoardAccessRow
+	err := row.Scan(
+		&i.ID,
+		&i.Visibility,
+		&i.Role,
+		&i.WorkspaceRole,
+	)
+	return i, err
+}
+
+const getBoardByID = `-- name: GetBoardByID :one
+SELECT id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by FROM boards
 WHERE id = $1 LIMIT 1
 `
 
-func (q *Queries) GetCardByID(ctx context.Context, id int32) (Card, error) {
-	row := q.db.QueryRow(ctx, getCardByID, id)
-	var i Card
+func (q *Queries) GetBoardByID(ctx context.Context, id int32) (Board, error) {
+	row := q.db.QueryRow(ctx, getBoardByID, id)
+	var i Board
 	err := row.Scan(
 		&i.ID,
-		&i.Title,
+		&i.Name,
 		&i.Description,
-		&i.ListID,
-		&i.Position,
+		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
-const getCardsByList = `-- name: GetCardsByList :many
-SELECT id, title, description, list_id, position, created_at, updated_at FROM cards
-WHERE list_id = $1
-ORDER BY position ASC
+const getBoardInvitations = `-- name: GetBoardInvitations :many
+SELECT id, board_id, email, role, invited_by, status, expires_at, responded_at, created_at FROM board_invitations
+WHERE board_id = $1
+ORDER BY created_at DESC
 `
 
-func (q *Queries) GetCardsByList(ctx context.Context, listID int32) ([]Card, error) {
-	rows, err := q.db.Query(ctx, getCardsByList, listID)
+type GetBoardInvitationsRow struct {
+	ID          int32
+	BoardID     int32
+	Email       string
+	Role        string
+	InvitedBy   int32
+	Status      string
+	ExpiresAt   pgtype.Timestamptz
+	RespondedAt pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) GetBoardInvitations(ctx context.Context, boardID int32) ([]GetBoardInvitationsRow, error) {
+	rows, err := q.db.Query(ctx, getBoardInvitations, boardID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Card
+	var items []GetBoardInvitationsRow
 	for rows.Next() {
-		var i Card
+		var i GetBoardInvitationsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.ListID,
-			&i.Position,
+			&i.BoardID,
+			&i.Email,
+			&i.Role,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.RespondedAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -290,137 +1255,2954 @@ func (q *Queries) GetCardsByList(ctx context.Context, listID int32) ([]Card, err
 	return items, nil
 }
 
-const getListByID = `-- name: GetListByID :one
-SELECT id, name, board_id, position, created_at, updated_at FROM lists
-WHERE id = $1 LIMIT 1
+const getBoardMember = `-- name: GetBoardMember :one
+SELECT board_id, user_id, role, created_at FROM board_members
+WHERE board_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetBoardMemberParams struct {
+	BoardID int32
+	UserID  int32
+}
+
+func (q *Queries) GetBoardMember(ctx context.Context, arg GetBoardMemberParams) (BoardMember, error) {
+	row := q.db.QueryRow(ctx, getBoardMember, arg.BoardID, arg.UserID)
+	var i BoardMember
+	err := row.Scan(
+		&i.BoardID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getBoardMembers = `-- name: GetBoardMembers :many
+SELECT m.board_id, m.user_id, m.role, m.created_at, u.name, u.email FROM board_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.board_id = $1
+ORDER BY m.created_at ASC
+`
+
+type GetBoardMembersRow struct {
+	BoardID   int32
+	UserID    int32
+	Role      string
+	CreatedAt pgtype.Timestamptz
+	Name      string
+	Email     string
+}
+
+func (q *Queries) GetBoardMembers(ctx context.Context, boardID int32) ([]GetBoardMembersRow, error) {
+	rows, err := q.db.Query(ctx, getBoardMembers, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBoardMembersRow
+	for rows.Next() {
+		var i GetBoardMembersRow
+		if err := rows.Scan(
+			&i.BoardID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBoardsByUser = `-- name: GetBoardsByUser :many
+SELECT b.id, b.name, b.description, b.created_by, b.created_at, b.updated_at, b.workspace_id, b.visibility, b.archived_at, b.deleted_at, b.deleted_by FROM boards b
+WHERE (EXISTS (
+  SELECT 1 FROM board_members m
+  WHERE m.board_id = b.id AND m.user_id = $1
+) OR EXISTS (
+  SELECT 1 FROM workspace_members wm
+  WHERE wm.workspace_id = b.workspace_id AND wm.user_id = $1
+    AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+))
+  AND b.deleted_at IS NULL
+  AND (b.archived_at IS NOT NULL) = $2::bool
+ORDER BY b.created_at DESC
+`
+
+type GetBoardsByUserParams struct {
+	UserID   int32
+	Archived bool
+}
+
+func (q *Queries) GetBoardsByUser(ctx context.Context, arg GetBoardsByUserParams) ([]Board, error) {
+	rows, err := q.db.Query(ctx, getBoardsByUser, arg.UserID, arg.Archived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Board
+	for rows.Next() {
+		var i Board
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.Visibility,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBoardsWithLongListRanks = `-- name: GetBoardsWithLongListRanks :many
+SELECT DISTINCT board_id FROM lists
+WHERE length(rank) > $1::int
+`
+
+func (q *Queries) GetBoardsWithLongListRanks(ctx context.Context, maxLength int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getBoardsWithLongListRanks, maxLength)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var board_id int32
+		if err := rows.Scan(&board_id); err != nil {
+			return nil, err
+		}
+		items = append(items, board_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardAccess = `-- name: GetCardAccess :one
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = $2
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE c.id = $1 AND c.deleted_at IS NULL AND l.deleted_at IS NULL AND b.deleted_at IS NULL LIMIT 1
+`
+
+type GetCardAccessParams struct {
+	ID     int32
+	UserID int32
+}
+
+type GetCardAccessRow struct {
+	ID            int32
+	Visibility    string
+	Role          string
+	WorkspaceRole string
+}
+
+func (q *Queries) GetCardAccess(ctx context.Context, arg GetCardAccessParams) (GetCardAccessRow, error) {
+	row := q.db.QueryRow(ctx, getCardAccess, arg.ID, arg.UserID)
+	var i GetCardAccessRow
+	err := row.Scan(
+		&i.ID,
+		&i.Visibility,
+		&i.Role,
+		&i.WorkspaceRole,
+	)
+	return i, err
+}
+
+const getCardAssignees = `-- name: GetCardAssignees :many
+SELECT a.card_id, a.user_id, a.assigned_by, a.created_at, u.name, u.email FROM card_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.card_id = $1
+ORDER BY a.created_at ASC
+`
+
+type GetCardAssigneesRow struct {
+	CardID     int32
+	UserID     int32
+	AssignedBy pgtype.Int4
+	CreatedAt  pgtype.Timestamptz
+	Name       string
+	Email      string
+}
+
+func (q *Queries) GetCardAssignees(ctx context.Context, cardID int32) ([]GetCardAssigneesRow, error) {
+	rows, err := q.db.Query(ctx, getCardAssignees, cardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCardAssigneesRow
+	for rows.Next() {
+		var i GetCardAssigneesRow
+		if err := rows.Scan(
+			&i.CardID,
+			&i.UserID,
+			&i.AssignedBy,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardAssigneesByBoard = `-- name: GetCardAssigneesByBoard :many
+SELECT a.card_id, a.user_id, a.assigned_by, a.created_at, u.name, u.email FROM card_assignees a
+JOIN users u ON u.id = a.user_id
+JOIN cards c ON c.id = a.card_id
+JOIN lists l ON l.id = c.list_id
+WHERE l.board_id = $1
+ORDER BY a.created_at ASC
+`
+
+type GetCardAssigneesByBoardRow struct {
+	CardID     int32
+	UserID     int32
+	AssignedBy pgtype.Int4
+	CreatedAt  pgtype.Timestamptz
+	Name       string
+	Email      string
+}
+
+func (q *Queries) GetCardAssigneesByBoard(ctx context.Context, boardID int32) ([]GetCardAssigneesByBoardRow, error) {
+	rows, err := q.db.Query(ctx, getCardAssigneesByBoard, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCardAssigneesByBoardRow
+	for rows.Next() {
+		var i GetCardAssigneesByBoardRow
+		if err := rows.Scan(
+			&i.CardID,
+			&i.UserID,
+			&i.AssignedBy,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardByID = `-- name: GetCardByID :one
+SELECT id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at FROM cards
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetCardByID(ctx context.Context, id int32) (Card, error) {
+	row := q.db.QueryRow(ctx, getCardByID, id)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.ListID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getCardForUpdate = `-- name: GetCardForUpdate :one
+SELECT id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at FROM cards
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetCardForUpdate(ctx context.Context, id int32) (Card, error) {
+	row := q.db.QueryRow(ctx, getCardForUpdate, id)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.ListID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getCardLabels = `-- name: GetCardLabels :many
+SELECT lb.id, lb.board_id, lb.name, lb.color, lb.created_at, lb.updated_at FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+WHERE cl.card_id = $1
+ORDER BY lb.name ASC
+`
+
+func (q *Queries) GetCardLabels(ctx context.Context, cardID int32) ([]Label, error) {
+	rows, err := q.db.Query(ctx, getCardLabels, cardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Label
+	for rows.Next() {
+		var i Label
+		if err := rows.Scan(
+			&i.ID,
+			&i.BoardID,
+			&i.Name,
+			&i.Color,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardLabelsByBoard = `-- name: GetCardLabelsByBoard :many
+SELECT cl.card_id, lb.id, lb.board_id, lb.name, lb.color, lb.created_at, lb.updated_at FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+WHERE lb.board_id = $1
+ORDER BY lb.name ASC
+`
+
+type GetCardLabelsByBoardRow struct {
+	CardID int32
+	Label  Label
+}
+
+func (q *Queries) GetCardLabelsByBoard(ctx context.Context, boardID int32) ([]GetCardLabelsByBoardRow, error) {
+	rows, err := q.db.Query(ctx, getCardLabelsByBoard, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCardLabelsByBoardRow
+	for rows.Next() {
+		var i GetCardLabelsByBoardRow
+		if err := rows.Scan(
+			&i.CardID,
+			&i.Label.ID,
+			&i.Label.BoardID,
+			&i.Label.Name,
+			&i.Label.Color,
+			&i.Label.CreatedAt,
+			&i.Label.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardLabelsByList = `-- name: GetCardLabelsByList :many
+SELECT cl.card_id, lb.id, lb.board_id, lb.name, lb.color, lb.created_at, lb.updated_at FROM card_labels cl
+JOIN labels lb ON lb.id = cl.label_id
+JOIN cards c ON c.id = cl.card_id
+WHERE c.list_id = $1
+ORDER BY lb.name ASC
+`
+
+type GetCardLabelsByListRow struct {
+	CardID int32
+	Label  Label
+}
+
+func (q *Queries) GetCardLabelsByList(ctx context.Context, listID int32) ([]GetCardLabelsByListRow, error) {
+	rows, err := q.db.Query(ctx, getCardLabelsByList, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCardLabelsByListRow
+	for rows.Next() {
+		var i GetCardLabelsByListRow
+		if err := rows.Scan(
+			&i.CardID,
+			&i.Label.ID,
+			&i.Label.BoardID,
+			&i.Label.Name,
+			&i.Label.Color,
+			&i.Label.CreatedAt,
+			&i.Label.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardReminders = `-- name: GetCardReminders :many
+SELECT id, card_id, offset_seconds, sent_for_due_at, created_by, created_at FROM card_reminders
+WHERE card_id = $1
+ORDER BY offset_seconds DESC
+`
+
+func (q *Queries) GetCardReminders(ctx context.Context, cardID int32) ([]CardReminder, error) {
+	rows, err := q.db.Query(ctx, getCardReminders, cardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CardReminder
+	for rows.Next() {
+		var i CardReminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.CardID,
+			&i.OffsetSeconds,
+			&i.SentForDueAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardsByBoard = `-- name: GetCardsByBoard :many
+SELECT c.id, c.title, c.description, c.list_id, c.created_at, c.updated_at, c.rank, c.archived_at, c.deleted_at, c.deleted_by, c.start_at, c.due_at, c.completed, c.completed_at FROM cards c
+JOIN lists l ON l.id = c.list_id
+WHERE l.board_id = $1 AND l.archived_at IS NULL AND c.archived_at IS NULL
+  AND l.deleted_at IS NULL AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::int[]), 0) = 0 OR EXISTS (
+    SELECT 1 FROM card_labels cl WHERE cl.card_id = c.id AND cl.label_id = ANY($2::int[])
+  ))
+ORDER BY c.list_id, c.rank
+`
+
+type GetCardsByBoardParams struct {
+	BoardID  int32
+	LabelIds []int32
+}
+
+// An empty label_ids matches every card, otherwise cards with any of the labels
+func (q *Queries) GetCardsByBoard(ctx context.Context, arg GetCardsByBoardParams) ([]Card, error) {
+	rows, err := q.db.Query(ctx, getCardsByBoard, arg.BoardID, arg.LabelIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ListID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.StartAt,
+			&i.DueAt,
+			&i.Completed,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardsByList = `-- name: GetCardsByList :many
+SELECT id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at FROM cards
+WHERE list_id = $1 AND deleted_at IS NULL AND (archived_at IS NOT NULL) = $2::bool
+  AND (COALESCE(cardinality($3::int[]), 0) = 0 OR EXISTS (
+    SELECT 1 FROM card_labels cl WHERE cl.card_id = cards.id AND cl.label_id = ANY($3::int[])
+  ))
+ORDER BY rank ASC
+`
+
+type GetCardsByListParams struct {
+	ListID   int32
+	Archived bool
+	LabelIds []int32
+}
+
+// An empty label_ids matches every card, otherwise cards with any of the labels
+func (q *Queries) GetCardsByList(ctx context.Context, arg GetCardsByListParams) ([]Card, error) {
+	rows, err := q.db.Query(ctx, getCardsByList, arg.ListID, arg.Archived, arg.LabelIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ListID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.StartAt,
+			&i.DueAt,
+			&i.Completed,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueCards = `-- name: GetDueCards :many
+SELECT l.board_id, b.name AS board_name, l.name AS list_name, c.id, c.title, c.description, c.list_id, c.created_at, c.updated_at, c.rank, c.archived_at, c.deleted_at, c.deleted_by, c.start_at, c.due_at, c.completed, c.completed_at FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.due_at < $1
+  AND ($2::timestamptz IS NULL OR c.due_at >= $2)
+  AND NOT c.completed
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+  AND (EXISTS (
+    SELECT 1 FROM board_members m
+    WHERE m.board_id = b.id AND m.user_id = $3
+  ) OR EXISTS (
+    SELECT 1 FROM workspace_members wm
+    WHERE wm.workspace_id = b.workspace_id AND wm.user_id = $3
+      AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+  ))
+ORDER BY c.due_at, c.id
+`
+
+type GetDueCardsParams struct {
+	DueBefore pgtype.Timestamptz
+	DueAfter  pgtype.Timestamptz
+	UserID    int32
+}
+
+type GetDueCardsRow struct {
+	BoardID   int32
+	BoardName string
+	ListName  string
+	Card      Card
+}
+
+// Open cards due in [due_after, due_before) on boards the user can see, soonest first.
+// A null due_after has no lower bound.
+func (q *Queries) GetDueCards(ctx context.Context, arg GetDueCardsParams) ([]GetDueCardsRow, error) {
+	rows, err := q.db.Query(ctx, getDueCards, arg.DueBefore, arg.DueAfter, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDueCardsRow
+	for rows.Next() {
+		var i GetDueCardsRow
+		if err := rows.Scan(
+			&i.BoardID,
+			&i.BoardName,
+			&i.ListName,
+			&i.Card.ID,
+			&i.Card.Title,
+			&i.Card.Description,
+			&i.Card.ListID,
+			&i.Card.CreatedAt,
+			&i.Card.UpdatedAt,
+			&i.Card.Rank,
+			&i.Card.ArchivedAt,
+			&i.Card.DeletedAt,
+			&i.Card.DeletedBy,
+			&i.Card.StartAt,
+			&i.Card.DueAt,
+			&i.Card.Completed,
+			&i.Card.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExportBoards = `-- name: GetExportBoards :many
+
+SELECT b.id, b.name, b.description, b.created_by, b.workspace_id, b.visibility, b.created_at, b.updated_at, b.archived_at, m.role FROM boards b
+JOIN board_members m ON m.board_id = b.id
+WHERE m.user_id = $1
+ORDER BY b.id
+`
+
+type GetExportBoardsRow struct {
+	ID          int32
+	Name        string
+	Description pgtype.Text
+	CreatedBy   int32
+	WorkspaceID pgtype.Int4
+	Visibility  string
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	ArchivedAt  pgtype.Timestamptz
+	Role        string
+}
+
+// ================================
+// ACCOUNT EXPORT AND DELETION QUERIES
+// ================================
+func (q *Queries) GetExportBoards(ctx context.Context, userID int32) ([]GetExportBoardsRow, error) {
+	rows, err := q.db.Query(ctx, getExportBoards, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExportBoardsRow
+	for rows.Next() {
+		var i GetExportBoardsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedBy,
+			&i.WorkspaceID,
+			&i.Visibility,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExportCards = `-- name: GetExportCards :many
+SELECT c.id, c.title, c.description, c.list_id, c.created_at, c.updated_at, c.rank, c.archived_at, c.deleted_at, c.deleted_by, c.start_at, c.due_at, c.completed, c.completed_at FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN board_members m ON m.board_id = l.board_id
+WHERE m.user_id = $1
+ORDER BY c.list_id, c.rank
+`
+
+func (q *Queries) GetExportCards(ctx context.Context, userID int32) ([]Card, error) {
+	rows, err := q.db.Query(ctx, getExportCards, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ListID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.StartAt,
+			&i.DueAt,
+			&i.Completed,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExportLists = `-- name: GetExportLists :many
+SELECT l.id, l.name, l.board_id, l.created_at, l.updated_at, l.rank, l.archived_at, l.deleted_at, l.deleted_by FROM lists l
+JOIN board_members m ON m.board_id = l.board_id
+WHERE m.user_id = $1
+ORDER BY l.board_id, l.rank
+`
+
+func (q *Queries) GetExportLists(ctx context.Context, userID int32) ([]List, error) {
+	rows, err := q.db.Query(ctx, getExportLists, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []List
+	for rows.Next() {
+		var i List
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BoardID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInvitationByID = `-- name: GetInvitationByID :one
+SELECT id, board_id, email, role, token_hash, invited_by, invitee_id, status, expires_at, responded_at, created_at FROM board_invitations
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetInvitationByID(ctx context.Context, id int32) (BoardInvitation, error) {
+	row := q.db.QueryRow(ctx, getInvitationByID, id)
+	var i BoardInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.InviteeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInvitationByTokenHash = `-- name: GetInvitationByTokenHash :one
+SELECT id, board_id, email, role, token_hash, invited_by, invitee_id, status, expires_at, responded_at, created_at FROM board_invitations
+WHERE token_hash = $1 LIMIT 1
+`
+
+func (q *Queries) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (BoardInvitation, error) {
+	row := q.db.QueryRow(ctx, getInvitationByTokenHash, tokenHash)
+	var i BoardInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.InviteeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLabelByID = `-- name: GetLabelByID :one
+SELECT id, board_id, name, color, created_at, updated_at FROM labels
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetLabelByID(ctx context.Context, id int32) (Label, error) {
+	row := q.db.QueryRow(ctx, getLabelByID, id)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Name,
+		&i.Color,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLabelsByBoard = `-- name: GetLabelsByBoard :many
+SELECT id, board_id, name, color, created_at, updated_at FROM labels
+WHERE board_id = $1
+ORDER BY name ASC
+`
+
+func (q *Queries) GetLabelsByBoard(ctx context.Context, boardID int32) ([]Label, error) {
+	rows, err := q.db.Query(ctx, getLabelsByBoard, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Label
+	for rows.Next() {
+		var i Label
+		if err := rows.Scan(
+			&i.ID,
+			&i.BoardID,
+			&i.Name,
+			&i.Color,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastCardRank = `-- name: GetLastCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND id <> $2
+ORDER BY rank DESC LIMIT 1
+`
+
+type GetLastCardRankParams struct {
+	ListID    int32
+	ExcludeID int32
+}
+
+func (q *Queries) GetLastCardRank(ctx context.Context, arg GetLastCardRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getLastCardRank, arg.ListID, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getLastListRank = `-- name: GetLastListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND id <> $2
+ORDER BY rank DESC LIMIT 1
+`
+
+type GetLastListRankParams struct {
+	BoardID   int32
+	ExcludeID int32
+}
+
+func (q *Queries) GetLastListRank(ctx context.Context, arg GetLastListRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getLastListRank, arg.BoardID, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getListAccess = `-- name: GetListAccess :one
+SELECT b.id, b.visibility, COALESCE(m.role, '')::text AS role, COALESCE(wm.role, '')::text AS workspace_role FROM lists l
+JOIN boards b ON b.id = l.board_id
+LEFT JOIN board_members m ON m.board_id = b.id AND m.user_id = $2
+LEFT JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE l.id = $1 AND l.deleted_at IS NULL AND b.deleted_at IS NULL LIMIT 1
+`
+
+type GetListAccessParams struct {
+	ID     int32
+	UserID int32
+}
+
+type GetListAccessRow struct {
+	ID            int32
+	Visibility    string
+	Role          string
+	WorkspaceRole string
+}
+
+func (q *Queries) GetListAccess(ctx context.Context, arg GetListAccessParams) (GetListAccessRow, error) {
+	row := q.db.QueryRow(ctx, getListAccess, arg.ID, arg.UserID)
+	var i GetListAccessRow
+	err := row.Scan(
+		&i.ID,
+		&i.Visibility,
+		&i.Role,
+		&i.WorkspaceRole,
+	)
+	return i, err
+}
+
+const getListByID = `-- name: GetListByID :one
+SELECT id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by FROM lists
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetListByID(ctx context.Context, id int32) (List, error) {
+	row := q.db.QueryRow(ctx, getListByID, id)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BoardID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const getListsByBoard = `-- name: GetListsByBoard :many
+SELECT id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by FROM lists
+WHERE board_id = $1 AND deleted_at IS NULL AND (archived_at IS NOT NULL) = $2::bool
+ORDER BY rank ASC
+`
+
+type GetListsByBoardParams struct {
+	BoardID  int32
+	Archived bool
+}
+
+func (q *Queries) GetListsByBoard(ctx context.Context, arg GetListsByBoardParams) ([]List, error) {
+	rows, err := q.db.Query(ctx, getListsByBoard, arg.BoardID, arg.Archived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []List
+	for rows.Next() {
+		var i List
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BoardID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListsWithLongCardRanks = `-- name: GetListsWithLongCardRanks :many
+SELECT DISTINCT list_id FROM cards
+WHERE length(rank) > $1::int
+`
+
+func (q *Queries) GetListsWithLongCardRanks(ctx context.Context, maxLength int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getListsWithLongCardRanks, maxLength)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var list_id int32
+		if err := rows.Scan(&list_id); err != nil {
+			return nil, err
+		}
+		items = append(items, list_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNextCardRank = `-- name: GetNextCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND rank > $2 AND id <> $3
+ORDER BY rank ASC LIMIT 1
+`
+
+type GetNextCardRankParams struct {
+	ListID    int32
+	Rank      string
+	ExcludeID int32
+}
+
+func (q *Queries) GetNextCardRank(ctx context.Context, arg GetNextCardRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getNextCardRank, arg.ListID, arg.Rank, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getNextListRank = `-- name: GetNextListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND rank > $2 AND id <> $3
+ORDER BY rank ASC LIMIT 1
+`
+
+type GetNextListRankParams struct {
+	BoardID   int32
+	Rank      string
+	ExcludeID int32
+}
+
+func (q *Queries) GetNextListRank(ctx context.Context, arg GetNextListRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getNextListRank, arg.BoardID, arg.Rank, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getOwnedBoards = `-- name: GetOwnedBoards :many
+SELECT b.id, b.name,
+  (SELECT COUNT(*) FROM users ou WHERE ou.id <> $1 AND (
+    EXISTS (SELECT 1 FROM board_members om WHERE om.board_id = b.id AND om.user_id = ou.id)
+    OR EXISTS (
+      SELECT 1 FROM workspace_members wm
+      WHERE wm.workspace_id = b.workspace_id AND wm.user_id = ou.id
+        AND (b.visibility = 'workspace' OR wm.role IN ('owner', 'admin'))
+    )
+  ))::int AS other_members
+FROM boards b
+WHERE b.created_by = $1
+   OR EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = $1 AND m.role = 'owner')
+ORDER BY b.id
+`
+
+type GetOwnedBoardsRow struct {
+	ID           int32
+	Name         string
+	OtherMembers int32
+}
+
+// Boards the user owns or created, with the number of other users who can reach them and could
+// take them over: board members, workspace owners and admins, and for workspace-visible
+// boards every workspace member
+func (q *Queries) GetOwnedBoards(ctx context.Context, userID int32) ([]GetOwnedBoardsRow, error) {
+	rows, err := q.db.Query(ctx, getOwnedBoards, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOwnedBoardsRow
+	for rows.Next() {
+		var i GetOwnedBoardsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.OtherMembers); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOwnedWorkspaces = `-- name: GetOwnedWorkspaces :many
+SELECT w.id, w.name,
+  (SELECT COUNT(*) FROM workspace_members om WHERE om.workspace_id = w.id AND om.user_id <> $1)::int AS other_members
+FROM workspaces w
+WHERE w.created_by = $1
+   OR EXISTS (SELECT 1 FROM workspace_members m WHERE m.workspace_id = w.id AND m.user_id = $1 AND m.role = 'owner')
+ORDER BY w.id
+`
+
+type GetOwnedWorkspacesRow struct {
+	ID           int32
+	Name         string
+	OtherMembers int32
+}
+
+// Workspaces the user owns or created, with the number of other members who could take them over
+func (q *Queries) GetOwnedWorkspaces(ctx context.Context, userID int32) ([]GetOwnedWorkspacesRow, error) {
+	rows, err := q.db.Query(ctx, getOwnedWorkspaces, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOwnedWorkspacesRow
+	for rows.Next() {
+		var i GetOwnedWorkspacesRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.OtherMembers); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingInvitationsForUser = `-- name: GetPendingInvitationsForUser :many
+SELECT i.id, i.board_id, b.name AS board_name, i.role, i.invited_by, i.expires_at, i.created_at FROM board_invitations i
+JOIN boards b ON b.id = i.board_id
+WHERE i.invitee_id = $1 AND i.status = 'pending' AND i.expires_at > NOW() AND b.deleted_at IS NULL
+ORDER BY i.created_at DESC
+`
+
+type GetPendingInvitationsForUserRow struct {
+	ID        int32
+	BoardID   int32
+	BoardName string
+	Role      string
+	InvitedBy int32
+	ExpiresAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetPendingInvitationsForUser(ctx context.Context, inviteeID pgtype.Int4) ([]GetPendingInvitationsForUserRow, error) {
+	rows, err := q.db.Query(ctx, getPendingInvitationsForUser, inviteeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingInvitationsForUserRow
+	for rows.Next() {
+		var i GetPendingInvitationsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BoardID,
+			&i.BoardName,
+			&i.Role,
+			&i.InvitedBy,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonalAccessTokenByHash = `-- name: GetPersonalAccessTokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at FROM personal_access_tokens
+WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW())
+`
+
+func (q *Queries) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, getPersonalAccessTokenByHash, tokenHash)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPersonalAccessTokens = `-- name: GetPersonalAccessTokens :many
+SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+type GetPersonalAccessTokensRow struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) GetPersonalAccessTokens(ctx context.Context, userID int32) ([]GetPersonalAccessTokensRow, error) {
+	rows, err := q.db.Query(ctx, getPersonalAccessTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPersonalAccessTokensRow
+	for rows.Next() {
+		var i GetPersonalAccessTokensRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Scopes,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPrevCardRank = `-- name: GetPrevCardRank :one
+SELECT rank FROM cards
+WHERE list_id = $1 AND rank < $2 AND id <> $3
+ORDER BY rank DESC LIMIT 1
+`
+
+type GetPrevCardRankParams struct {
+	ListID    int32
+	Rank      string
+	ExcludeID int32
+}
+
+func (q *Queries) GetPrevCardRank(ctx context.Context, arg GetPrevCardRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getPrevCardRank, arg.ListID, arg.Rank, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getPrevListRank = `-- name: GetPrevListRank :one
+SELECT rank FROM lists
+WHERE board_id = $1 AND rank < $2 AND id <> $3
+ORDER BY rank DESC LIMIT 1
+`
+
+type GetPrevListRankParams struct {
+	BoardID   int32
+	Rank      string
+	ExcludeID int32
+}
+
+func (q *Queries) GetPrevListRank(ctx context.Context, arg GetPrevListRankParams) (string, error) {
+	row := q.db.QueryRow(ctx, getPrevListRank, arg.BoardID, arg.Rank, arg.ExcludeID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getRankedCardIDs = `-- name: GetRankedCardIDs :many
+SELECT id FROM cards
+WHERE list_id = $1
+ORDER BY rank ASC
+`
+
+func (q *Queries) GetRankedCardIDs(ctx context.Context, listID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getRankedCardIDs, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRankedListIDs = `-- name: GetRankedListIDs :many
+SELECT id FROM lists
+WHERE board_id = $1
+ORDER BY rank ASC
+`
+
+func (q *Queries) GetRankedListIDs(ctx context.Context, boardID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getRankedListIDs, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedBoards = `-- name: GetTrashedBoards :many
+
+SELECT id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by FROM boards
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+// ================================
+// TRASH QUERIES
+// ================================
+func (q *Queries) GetTrashedBoards(ctx context.Context, deletedBy pgtype.Int4) ([]Board, error) {
+	rows, err := q.db.Query(ctx, getTrashedBoards, deletedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Board
+	for rows.Next() {
+		var i Board
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.Visibility,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedCard = `-- name: GetTrashedCard :one
+SELECT l.board_id, (l.deleted_at IS NOT NULL OR b.deleted_at IS NOT NULL)::bool AS parent_deleted FROM cards c
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.id = $1 AND c.deleted_by = $2 AND c.deleted_at IS NOT NULL
+LIMIT 1
+`
+
+type GetTrashedCardParams struct {
+	ID     int32
+	UserID pgtype.Int4
+}
+
+type GetTrashedCardRow struct {
+	BoardID       int32
+	ParentDeleted bool
+}
+
+// A card in the user's trash, and whether its list or board is in a trash too
+func (q *Queries) GetTrashedCard(ctx context.Context, arg GetTrashedCardParams) (GetTrashedCardRow, error) {
+	row := q.db.QueryRow(ctx, getTrashedCard, arg.ID, arg.UserID)
+	var i GetTrashedCardRow
+	err := row.Scan(&i.BoardID, &i.ParentDeleted)
+	return i, err
+}
+
+const getTrashedCards = `-- name: GetTrashedCards :many
+SELECT id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at FROM cards
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetTrashedCards(ctx context.Context, deletedBy pgtype.Int4) ([]Card, error) {
+	rows, err := q.db.Query(ctx, getTrashedCards, deletedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ListID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.StartAt,
+			&i.DueAt,
+			&i.Completed,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedList = `-- name: GetTrashedList :one
+SELECT l.board_id, (b.deleted_at IS NOT NULL)::bool AS board_deleted FROM lists l
+JOIN boards b ON b.id = l.board_id
+WHERE l.id = $1 AND l.deleted_by = $2 AND l.deleted_at IS NOT NULL
+LIMIT 1
+`
+
+type GetTrashedListParams struct {
+	ID     int32
+	UserID pgtype.Int4
+}
+
+type GetTrashedListRow struct {
+	BoardID      int32
+	BoardDeleted bool
+}
+
+// A list in the user's trash, and whether its board is in a trash too
+func (q *Queries) GetTrashedList(ctx context.Context, arg GetTrashedListParams) (GetTrashedListRow, error) {
+	row := q.db.QueryRow(ctx, getTrashedList, arg.ID, arg.UserID)
+	var i GetTrashedListRow
+	err := row.Scan(&i.BoardID, &i.BoardDeleted)
+	return i, err
+}
+
+const getTrashedLists = `-- name: GetTrashedLists :many
+SELECT id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by FROM lists
+WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetTrashedLists(ctx context.Context, deletedBy pgtype.Int4) ([]List, error) {
+	rows, err := q.db.Query(ctx, getTrashedLists, deletedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []List
+	for rows.Next() {
+		var i List
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BoardID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, hashed_password, created_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, has_password FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, name, email, hashed_password, created_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, has_password FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
+	)
+	return i, err
+}
+
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+
+SELECT u.id, u.name, u.email, u.hashed_password, u.created_at, u.email_verified_at, u.totp_secret, u.totp_enabled_at, u.totp_last_step, u.has_password FROM users u
+JOIN user_identities i ON i.user_id = u.id
+WHERE i.provider = $1 AND i.subject = $2
+`
+
+type GetUserByIdentityParams struct {
+	Provider string
+	Subject  string
+}
+
+// ================================
+// SINGLE SIGN-ON QUERIES
+// ================================
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIdentity, arg.Provider, arg.Subject)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
+	)
+	return i, err
+}
+
+const getUserIdentities = `-- name: GetUserIdentities :many
+SELECT id, provider, subject, email, created_at, last_login_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+type GetUserIdentitiesRow struct {
+	ID          int32
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   pgtype.Timestamptz
+	LastLoginAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserIdentities(ctx context.Context, userID int32) ([]GetUserIdentitiesRow, error) {
+	rows, err := q.db.Query(ctx, getUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserIdentitiesRow
+	for rows.Next() {
+		var i GetUserIdentitiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Provider,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+			&i.LastLoginAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserNotifications = `-- name: GetUserNotifications :many
+SELECT id, user_id, card_id, kind, message, read_at, created_at FROM notifications
+WHERE user_id = $1 AND (read_at IS NULL OR NOT $2::bool)
+ORDER BY created_at DESC
+LIMIT 100
+`
+
+type GetUserNotificationsParams struct {
+	UserID     int32
+	UnreadOnly bool
+}
+
+func (q *Queries) GetUserNotifications(ctx context.Context, arg GetUserNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, getUserNotifications, arg.UserID, arg.UnreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CardID,
+			&i.Kind,
+			&i.Message,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSecurityEvents = `-- name: GetUserSecurityEvents :many
+SELECT id, event_type, email, ip_address, user_agent, details, created_at FROM security_events
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+type GetUserSecurityEventsRow struct {
+	ID        int64
+	EventType string
+	Email     string
+	IpAddress string
+	UserAgent string
+	Details   string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserSecurityEvents(ctx context.Context, userID pgtype.Int4) ([]GetUserSecurityEventsRow, error) {
+	rows, err := q.db.Query(ctx, getUserSecurityEvents, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSecurityEventsRow
+	for rows.Next() {
+		var i GetUserSecurityEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.Email,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSessions = `-- name: GetUserSessions :many
+SELECT us.id, us.ip_address, us.user_agent, us.device, us.created_at, us.last_seen_at, s.expiry,
+  (us.token = $2)::boolean AS current
+FROM user_sessions us
+JOIN sessions s ON s.token = us.token
+WHERE us.user_id = $1 AND s.expiry > NOW()
+ORDER BY us.last_seen_at DESC
+`
+
+type GetUserSessionsParams struct {
+	UserID       int32
+	CurrentToken string
+}
+
+type GetUserSessionsRow struct {
+	ID         int32
+	IpAddress  string
+	UserAgent  string
+	Device     string
+	CreatedAt  pgtype.Timestamptz
+	LastSeenAt pgtype.Timestamptz
+	Expiry     pgtype.Timestamptz
+	Current    bool
+}
+
+func (q *Queries) GetUserSessions(ctx context.Context, arg GetUserSessionsParams) ([]GetUserSessionsRow, error) {
+	rows, err := q.db.Query(ctx, getUserSessions, arg.UserID, arg.CurrentToken)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSessionsRow
+	for rows.Next() {
+		var i GetUserSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Device,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.Expiry,
+			&i.Current,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAccess = `-- name: GetWorkspaceAccess :one
+SELECT w.id, COALESCE(m.role, '')::text AS role FROM workspaces w
+LEFT JOIN workspace_members m ON m.workspace_id = w.id AND m.user_id = $2
+WHERE w.id = $1 LIMIT 1
+`
+
+type GetWorkspaceAccessParams struct {
+	ID     int32
+	UserID int32
+}
+
+type GetWorkspaceAccessRow struct {
+	ID   int32
+	Role string
+}
+
+func (q *Queries) GetWorkspaceAccess(ctx context.Context, arg GetWorkspaceAccessParams) (GetWorkspaceAccessRow, error) {
+	row := q.db.QueryRow(ctx, getWorkspaceAccess, arg.ID, arg.UserID)
+	var i GetWorkspaceAccessRow
+	err := row.Scan(&i.ID, &i.Role)
+	return i, err
+}
+
+const getWorkspaceBoardIDs = `-- name: GetWorkspaceBoardIDs :many
+SELECT id FROM boards
+WHERE workspace_id = $1
+`
+
+func (q *Queries) GetWorkspaceBoardIDs(ctx context.Context, workspaceID pgtype.Int4) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getWorkspaceBoardIDs, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBoards = `-- name: GetWorkspaceBoards :many
+SELECT b.id, b.name, b.description, b.created_by, b.created_at, b.updated_at, b.workspace_id, b.visibility, b.archived_at, b.deleted_at, b.deleted_by FROM boards b
+JOIN workspace_members wm ON wm.workspace_id = b.workspace_id AND wm.user_id = $2
+WHERE b.workspace_id = $1 AND b.archived_at IS NULL AND b.deleted_at IS NULL
+  AND (
+    wm.role IN ('owner', 'admin')
+    OR b.visibility = 'workspace'
+    OR EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = $2)
+  )
+ORDER BY b.created_at DESC
+`
+
+type GetWorkspaceBoardsParams struct {
+	WorkspaceID pgtype.Int4
+	UserID      int32
+}
+
+func (q *Queries) GetWorkspaceBoards(ctx context.Context, arg GetWorkspaceBoardsParams) ([]Board, error) {
+	rows, err := q.db.Query(ctx, getWorkspaceBoards, arg.WorkspaceID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Board
+	for rows.Next() {
+		var i Board
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.Visibility,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceByID = `-- name: GetWorkspaceByID :one
+SELECT id, name, description, created_by, created_at, updated_at FROM workspaces
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWorkspaceByID(ctx context.Context, id int32) (Workspace, error) {
+	row := q.db.QueryRow(ctx, getWorkspaceByID, id)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceMember = `-- name: GetWorkspaceMember :one
+SELECT workspace_id, user_id, role, created_at FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetWorkspaceMemberParams struct {
+	WorkspaceID int32
+	UserID      int32
+}
+
+func (q *Queries) GetWorkspaceMember(ctx context.Context, arg GetWorkspaceMemberParams) (WorkspaceMember, error) {
+	row := q.db.QueryRow(ctx, getWorkspaceMember, arg.WorkspaceID, arg.UserID)
+	var i WorkspaceMember
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceMembers = `-- name: GetWorkspaceMembers :many
+SELECT m.workspace_id, m.user_id, m.role, m.created_at, u.name, u.email FROM workspace_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.workspace_id = $1
+ORDER BY m.created_at ASC
+`
+
+type GetWorkspaceMembersRow struct {
+	WorkspaceID int32
+	UserID      int32
+	Role        string
+	CreatedAt   pgtype.Timestamptz
+	Name        string
+	Email       string
+}
+
+func (q *Queries) GetWorkspaceMembers(ctx context.Context, workspaceID int32) ([]GetWorkspaceMembersRow, error) {
+	rows, err := q.db.Query(ctx, getWorkspaceMembers, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceMembersRow
+	for rows.Next() {
+		var i GetWorkspaceMembersRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspacesByUser = `-- name: GetWorkspacesByUser :many
+SELECT w.id, w.name, w.description, w.created_by, w.created_at, w.updated_at, m.role FROM workspaces w
+JOIN workspace_members m ON m.workspace_id = w.id
+WHERE m.user_id = $1
+ORDER BY w.created_at DESC
+`
+
+type GetWorkspacesByUserRow struct {
+	ID          int32
+	Name        string
+	Description pgtype.Text
+	CreatedBy   int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	Role        string
+}
+
+func (q *Queries) GetWorkspacesByUser(ctx context.Context, userID int32) ([]GetWorkspacesByUserRow, error) {
+	rows, err := q.db.Query(ctx, getWorkspacesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacesByUserRow
+	for rows.Next() {
+		var i GetWorkspacesByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const invalidateEmailVerificationTokens = `-- name: InvalidateEmailVerificationTokens :exec
+UPDATE email_verification_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidateEmailVerificationTokens(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, invalidateEmailVerificationTokens, userID)
+	return err
+}
+
+const invalidatePasswordResetTokens = `-- name: InvalidatePasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidatePasswordResetTokens(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, invalidatePasswordResetTokens, userID)
+	return err
+}
+
+const lockBoards = `-- name: LockBoards :many
+SELECT id FROM boards
+WHERE id = ANY($1::int[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockBoards(ctx context.Context, ids []int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, lockBoards, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDueReminder = `-- name: LockDueReminder :one
+SELECT r.id, r.card_id, c.title AS card_title, c.due_at, l.board_id, b.name AS board_name FROM card_reminders r
+JOIN cards c ON c.id = r.card_id
+JOIN lists l ON l.id = c.list_id
+JOIN boards b ON b.id = l.board_id
+WHERE c.due_at > NOW()
+  AND c.due_at - r.offset_seconds * INTERVAL '1 second' <= NOW()
+  AND r.sent_for_due_at IS DISTINCT FROM c.due_at
+  AND NOT c.completed
+  AND c.archived_at IS NULL AND c.deleted_at IS NULL
+  AND l.archived_at IS NULL AND l.deleted_at IS NULL
+  AND b.archived_at IS NULL AND b.deleted_at IS NULL
+ORDER BY c.due_at
+LIMIT 1
+FOR UPDATE OF r SKIP LOCKED
+`
+
+type LockDueReminderRow struct {
+	ID        int32
+	CardID    int32
+	CardTitle string
+	DueAt     pgtype.Timestamptz
+	BoardID   int32
+	BoardName string
+}
+
+// Claims one reminder that should fire now: its card is open, due in the future and
+// within the offset, and it has not fired for this due date. Other instances skip it.
+func (q *Queries) LockDueReminder(ctx context.Context) (LockDueReminderRow, error) {
+	row := q.db.QueryRow(ctx, lockDueReminder)
+	var i LockDueReminderRow
+	err := row.Scan(
+		&i.ID,
+		&i.CardID,
+		&i.CardTitle,
+		&i.DueAt,
+		&i.BoardID,
+		&i.BoardName,
+	)
+	return i, err
+}
+
+const lockLists = `-- name: LockLists :many
+SELECT id FROM lists
+WHERE id = ANY($1::int[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockLists(ctx context.Context, ids []int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, lockLists, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLoginAttempts = `-- name: LockLoginAttempts :one
+INSERT INTO login_attempts (key, failures, last_failure_at)
+VALUES ($1, 0, NOW())
+ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+RETURNING failures, last_failure_at
+`
+
+type LockLoginAttemptsRow struct {
+	Failures      int32
+	LastFailureAt pgtype.Timestamptz
+}
+
+// Creates the streak if it is missing and locks it for the rest of the transaction,
+// so even the first attempts on a key are checked one after another
+func (q *Queries) LockLoginAttempts(ctx context.Context, key string) (LockLoginAttemptsRow, error) {
+	row := q.db.QueryRow(ctx, lockLoginAttempts, key)
+	var i LockLoginAttemptsRow
+	err := row.Scan(&i.Failures, &i.LastFailureAt)
+	return i, err
+}
+
+const markEmailVerified = `-- name: MarkEmailVerified :one
+UPDATE users
+SET email_verified_at = NOW()
+WHERE id = $1 AND email = $2
+RETURNING id, name, email, hashed_password, created_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, has_password
+`
+
+type MarkEmailVerifiedParams struct {
+	ID    int32
+	Email string
+}
+
+func (q *Queries) MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) (User, error) {
+	row := q.db.QueryRow(ctx, markEmailVerified, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
+	)
+	return i, err
+}
+
+const markLoginThrottled = `-- name: MarkLoginThrottled :execrows
+UPDATE login_attempts SET throttled_at = NOW()
+WHERE key = $1 AND (throttled_at IS NULL OR throttled_at < last_failure_at)
+`
+
+// Only the first throttled attempt after each failure marks the streak
+func (q *Queries) MarkLoginThrottled(ctx context.Context, key string) (int64, error) {
+	result, err := q.db.Exec(ctx, markLoginThrottled, key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, card_id, kind, message, read_at, created_at
+`
+
+type MarkNotificationReadParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, markNotificationRead, arg.ID, arg.UserID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CardID,
+		&i.Kind,
+		&i.Message,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markReminderSent = `-- name: MarkReminderSent :exec
+UPDATE card_reminders
+SET sent_for_due_at = $1
+WHERE id = $2
+`
+
+type MarkReminderSentParams struct {
+	SentForDueAt pgtype.Timestamptz
+	ID           int32
+}
+
+func (q *Queries) MarkReminderSent(ctx context.Context, arg MarkReminderSentParams) error {
+	_, err := q.db.Exec(ctx, markReminderSent, arg.SentForDueAt, arg.ID)
+	return err
+}
+
+const moveCard = `-- name: MoveCard :one
+UPDATE cards
+SET list_id = $1, rank = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at
+`
+
+type MoveCardParams struct {
+	ListID int32
+	Rank   string
+	ID     int32
+}
+
+func (q *Queries) MoveCard(ctx context.Context, arg MoveCardParams) (Card, error) {
+	row := q.db.QueryRow(ctx, moveCard, arg.ListID, arg.Rank, arg.ID)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.ListID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const moveList = `-- name: MoveList :one
+UPDATE lists
+SET board_id = $1, rank = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by
+`
+
+type MoveListParams struct {
+	BoardID int32
+	Rank    string
+	ID      int32
+}
+
+func (q *Queries) MoveList(ctx context.Context, arg MoveListParams) (List, error) {
+	row := q.db.QueryRow(ctx, moveList, arg.BoardID, arg.Rank, arg.ID)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BoardID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const purgeDeletedBoards = `-- name: PurgeDeletedBoards :execrows
+DELETE FROM boards
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedBoards(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedBoards, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedCards = `-- name: PurgeDeletedCards :execrows
+DELETE FROM cards
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedCards(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedCards, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedLists = `-- name: PurgeDeletedLists :execrows
+DELETE FROM lists
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedLists(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedLists, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+
+INSERT INTO login_attempts (key, failures, last_failure_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+      WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2::float8) THEN 1
+      ELSE login_attempts.failures + 1
+    END,
+    last_failure_at = NOW()
+RETURNING failures, last_failure_at
+`
+
+type RecordLoginFailureParams struct {
+	Key           string
+	WindowSeconds float64
+}
+
+type RecordLoginFailureRow struct {
+	Failures      int32
+	LastFailureAt pgtype.Timestamptz
+}
+
+// ================================
+// LOGIN GUARD QUERIES
+// ================================
+// Failures older than the window no longer count, so the streak starts again
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (RecordLoginFailureRow, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Key, arg.WindowSeconds)
+	var i RecordLoginFailureRow
+	err := row.Scan(&i.Failures, &i.LastFailureAt)
+	return i, err
+}
+
+const releaseLoginAttempt = `-- name: ReleaseLoginAttempt :exec
+UPDATE login_attempts SET failures = failures - 1
+WHERE key = $1 AND failures > 0
+`
+
+func (q *Queries) ReleaseLoginAttempt(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, releaseLoginAttempt, key)
+	return err
+}
+
+const respondToInvitation = `-- name: RespondToInvitation :one
+UPDATE board_invitations
+SET status = $1, invitee_id = $2, responded_at = NOW()
+WHERE id = $3 AND status = 'pending'
+RETURNING id, board_id, email, role, token_hash, invited_by, invitee_id, status, expires_at, responded_at, created_at
+`
+
+type RespondToInvitationParams struct {
+	Status    string
+	InviteeID pgtype.Int4
+	ID        int32
+}
+
+func (q *Queries) RespondToInvitation(ctx context.Context, arg RespondToInvitationParams) (BoardInvitation, error) {
+	row := q.db.QueryRow(ctx, respondToInvitation, arg.Status, arg.InviteeID, arg.ID)
+	var i BoardInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.InviteeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const restoreBoard = `-- name: RestoreBoard :one
+UPDATE boards
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_by = $2 AND deleted_at IS NOT NULL
+RETURNING id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by
+`
+
+type RestoreBoardParams struct {
+	ID     int32
+	UserID pgtype.Int4
+}
+
+func (q *Queries) RestoreBoard(ctx context.Context, arg RestoreBoardParams) (Board, error) {
+	row := q.db.QueryRow(ctx, restoreBoard, arg.ID, arg.UserID)
+	var i Board
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const restoreCard = `-- name: RestoreCard :one
+UPDATE cards
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_by = $2 AND deleted_at IS NOT NULL
+RETURNING id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at
+`
+
+type RestoreCardParams struct {
+	ID     int32
+	UserID pgtype.Int4
+}
+
+func (q *Queries) RestoreCard(ctx context.Context, arg RestoreCardParams) (Card, error) {
+	row := q.db.QueryRow(ctx, restoreCard, arg.ID, arg.UserID)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.ListID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const restoreList = `-- name: RestoreList :one
+UPDATE lists
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_by = $2 AND deleted_at IS NOT NULL
+RETURNING id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by
+`
+
+type RestoreListParams struct {
+	ID     int32
+	UserID pgtype.Int4
+}
+
+func (q *Queries) RestoreList(ctx context.Context, arg RestoreListParams) (List, error) {
+	row := q.db.QueryRow(ctx, restoreList, arg.ID, arg.UserID)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BoardID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const revokeBoardInvitation = `-- name: RevokeBoardInvitation :one
+UPDATE board_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE id = $1 AND board_id = $2 AND status = 'pending'
+RETURNING id, board_id, email, role, token_hash, invited_by, invitee_id, status, expires_at, responded_at, created_at
+`
+
+type RevokeBoardInvitationParams struct {
+	ID      int32
+	BoardID int32
+}
+
+func (q *Queries) RevokeBoardInvitation(ctx context.Context, arg RevokeBoardInvitationParams) (BoardInvitation, error) {
+	row := q.db.QueryRow(ctx, revokeBoardInvitation, arg.ID, arg.BoardID)
+	var i BoardInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.InviteeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const revokePendingInvitations = `-- name: RevokePendingInvitations :exec
+UPDATE board_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE board_id = $1 AND LOWER(email) = LOWER($2) AND status = 'pending'
+`
+
+type RevokePendingInvitationsParams struct {
+	BoardID int32
+	Email   string
+}
+
+func (q *Queries) RevokePendingInvitations(ctx context.Context, arg RevokePendingInvitationsParams) error {
+	_, err := q.db.Exec(ctx, revokePendingInvitations, arg.BoardID, arg.Email)
+	return err
+}
+
+const setBoardArchived = `-- name: SetBoardArchived :one
+UPDATE boards
+SET archived_at = CASE WHEN $1::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = $2
+RETURNING id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by
+`
+
+type SetBoardArchivedParams struct {
+	Archived bool
+	ID       int32
+}
+
+func (q *Queries) SetBoardArchived(ctx context.Context, arg SetBoardArchivedParams) (Board, error) {
+	row := q.db.QueryRow(ctx, setBoardArchived, arg.Archived, arg.ID)
+	var i Board
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const setCardArchived = `-- name: SetCardArchived :one
+UPDATE cards
+SET archived_at = CASE WHEN $1::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = $2
+RETURNING id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at
+`
+
+type SetCardArchivedParams struct {
+	Archived bool
+	ID       int32
+}
+
+func (q *Queries) SetCardArchived(ctx context.Context, arg SetCardArchivedParams) (Card, error) {
+	row := q.db.QueryRow(ctx, setCardArchived, arg.Archived, arg.ID)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.ListID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const setCardRanks = `-- name: SetCardRanks :exec
+UPDATE cards
+SET rank = r.rank
+FROM (SELECT unnest($1::int[]) AS id, unnest($2::text[]) AS rank) r
+WHERE cards.id = r.id
 `
 
-func (q *Queries) GetListByID(ctx context.Context, id int32) (List, error) {
-	row := q.db.QueryRow(ctx, getListByID, id)
+type SetCardRanksParams struct {
+	Ids   []int32
+	Ranks []string
+}
+
+func (q *Queries) SetCardRanks(ctx context.Context, arg SetCardRanksParams) error {
+	_, err := q.db.Exec(ctx, setCardRanks, arg.Ids, arg.Ranks)
+	return err
+}
+
+const setListArchived = `-- name: SetListArchived :one
+UPDATE lists
+SET archived_at = CASE WHEN $1::bool THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+WHERE id = $2
+RETURNING id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by
+`
+
+type SetListArchivedParams struct {
+	Archived bool
+	ID       int32
+}
+
+func (q *Queries) SetListArchived(ctx context.Context, arg SetListArchivedParams) (List, error) {
+	row := q.db.QueryRow(ctx, setListArchived, arg.Archived, arg.ID)
 	var i List
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.BoardID,
-		&i.Position,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
-const getListsByBoard = `-- name: GetListsByBoard :many
-SELECT id, name, board_id, position, created_at, updated_at FROM lists
-WHERE board_id = $1
-ORDER BY position ASC
+const setListRanks = `-- name: SetListRanks :exec
+UPDATE lists
+SET rank = r.rank
+FROM (SELECT unnest($1::int[]) AS id, unnest($2::text[]) AS rank) r
+WHERE lists.id = r.id
+`
+
+type SetListRanksParams struct {
+	Ids   []int32
+	Ranks []string
+}
+
+func (q *Queries) SetListRanks(ctx context.Context, arg SetListRanksParams) error {
+	_, err := q.db.Exec(ctx, setListRanks, arg.Ids, arg.Ranks)
+	return err
+}
+
+const setPendingTOTPSecret = `-- name: SetPendingTOTPSecret :exec
+
+UPDATE users
+SET totp_secret = $2, totp_enabled_at = NULL, totp_last_step = NULL
+WHERE id = $1 AND totp_enabled_at IS NULL
+`
+
+type SetPendingTOTPSecretParams struct {
+	ID         int32
+	TotpSecret pgtype.Text
+}
+
+// ================================
+// TWO-FACTOR AUTHENTICATION QUERIES
+// ================================
+// Enrollment is only confirmed once the user proves they can generate codes
+func (q *Queries) SetPendingTOTPSecret(ctx context.Context, arg SetPendingTOTPSecretParams) error {
+	_, err := q.db.Exec(ctx, setPendingTOTPSecret, arg.ID, arg.TotpSecret)
+	return err
+}
+
+const touchPersonalAccessToken = `-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+// Throttled to once a minute so busy scripts don't write on every request
+func (q *Queries) TouchPersonalAccessToken(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, touchPersonalAccessToken, id)
+	return err
+}
+
+const touchUserIdentity = `-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = NOW(), email = $3
+WHERE provider = $1 AND subject = $2
+`
+
+type TouchUserIdentityParams struct {
+	Provider string
+	Subject  string
+	Email    string
+}
+
+func (q *Queries) TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error {
+	_, err := q.db.Exec(ctx, touchUserIdentity, arg.Provider, arg.Subject, arg.Email)
+	return err
+}
+
+const trackUserSession = `-- name: TrackUserSession :exec
+
+INSERT INTO user_sessions (
+  user_id,
+  token,
+  ip_address,
+  user_agent,
+  device
+)
+SELECT $1::int, $2::text, $3::text,
+  $4::text, $5::text
+WHERE NOT EXISTS (
+  SELECT 1 FROM user_sessions
+  WHERE token = $2 AND last_seen_at >= NOW() - INTERVAL '1 minute'
+)
+ON CONFLICT (token) DO UPDATE
+SET ip_address = EXCLUDED.ip_address, last_seen_at = NOW()
+WHERE user_sessions.last_seen_at < NOW() - INTERVAL '1 minute'
+`
+
+type TrackUserSessionParams struct {
+	UserID    int32
+	Token     string
+	IpAddress string
+	UserAgent string
+	Device    string
+}
+
+// ================================
+// SESSION QUERIES
+// ================================
+// Throttled to once a minute per session: a session seen within the last minute is
+// only read, so busy clients neither lock the row nor use up ids from the sequence.
+func (q *Queries) TrackUserSession(ctx context.Context, arg TrackUserSessionParams) error {
+	_, err := q.db.Exec(ctx, trackUserSession,
+		arg.UserID,
+		arg.Token,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Device,
+	)
+	return err
+}
+
+const transferBoardOwnership = `-- name: TransferBoardOwnership :execrows
+WITH promoted AS (
+  INSERT INTO board_members (board_id, user_id, role)
+  SELECT b.id, $1, 'owner' FROM boards b
+  WHERE b.id = $2 AND (
+    EXISTS (SELECT 1 FROM board_members m WHERE m.board_id = b.id AND m.user_id = $1)
+    OR EXISTS (
+      SELECT 1 FROM workspace_members wm
+      WHERE wm.workspace_id = b.workspace_id AND wm.user_id = $1
+        AND (b.visibility = 'workspace' OR wm.role IN ('owner', 'admin'))
+    )
+  )
+  ON CONFLICT (board_id, user_id) DO UPDATE SET role = 'owner'
+  RETURNING board_members.board_id
+)
+UPDATE boards
+SET created_by = $1
+WHERE boards.id IN (SELECT promoted.board_id FROM promoted)
 `
 
-func (q *Queries) GetListsByBoard(ctx context.Context, boardID int32) ([]List, error) {
-	rows, err := q.db.Query(ctx, getListsByBoard, boardID)
+type TransferBoardOwnershipParams struct {
+	NewOwnerID int32
+	BoardID    int32
+}
+
+// The new owner must already reach the board, as a member or through its workspace, and becomes
+// an owning member; created_by follows so the board survives the old owner's deletion
+func (q *Queries) TransferBoardOwnership(ctx context.Context, arg TransferBoardOwnershipParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transferBoardOwnership, arg.NewOwnerID, arg.BoardID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []List
-	for rows.Next() {
-		var i List
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.BoardID,
-			&i.Position,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return result.RowsAffected(), nil
+}
+
+const transferWorkspaceOwnership = `-- name: TransferWorkspaceOwnership :execrows
+WITH promoted AS (
+  UPDATE workspace_members
+  SET role = 'owner'
+  WHERE workspace_members.workspace_id = $2 AND workspace_members.user_id = $1
+  RETURNING workspace_members.workspace_id
+)
+UPDATE workspaces
+SET created_by = $1
+WHERE workspaces.id IN (SELECT promoted.workspace_id FROM promoted)
+`
+
+type TransferWorkspaceOwnershipParams struct {
+	NewOwnerID  int32
+	WorkspaceID int32
+}
+
+// The new owner must already be a member; created_by follows so the workspace survives the old owner's deletion
+func (q *Queries) TransferWorkspaceOwnership(ctx context.Context, arg TransferWorkspaceOwnershipParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transferWorkspaceOwnership, arg.NewOwnerID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
-const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, hashed_password, created_at FROM users
-WHERE email = $1 LIMIT 1
+const unassignCard = `-- name: UnassignCard :exec
+DELETE FROM card_assignees
+WHERE card_id = $1 AND user_id = $2
 `
 
-func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByEmail, email)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Email,
-		&i.HashedPassword,
-		&i.CreatedAt,
-	)
-	return i, err
+type UnassignCardParams struct {
+	CardID int32
+	UserID int32
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, hashed_password, created_at FROM users
-WHERE id = $1 LIMIT 1
+func (q *Queries) UnassignCard(ctx context.Context, arg UnassignCardParams) error {
+	_, err := q.db.Exec(ctx, unassignCard, arg.CardID, arg.UserID)
+	return err
+}
+
+const unassignUsersWithoutAccess = `-- name: UnassignUsersWithoutAccess :exec
+DELETE FROM card_assignees a
+USING cards c, lists l, boards b
+WHERE a.card_id = c.id AND c.list_id = l.id AND b.id = l.board_id
+  AND b.id = ANY($1::int[])
+  AND NOT EXISTS (
+    SELECT 1 FROM board_members m
+    WHERE m.board_id = b.id AND m.user_id = a.user_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM workspace_members wm
+    WHERE wm.workspace_id = b.workspace_id AND wm.user_id = a.user_id
+      AND (wm.role IN ('owner', 'admin') OR b.visibility = 'workspace')
+  )
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByID, id)
-	var i User
+// Assignees must be able to see the card's board, like in GetBoardsByUser
+func (q *Queries) UnassignUsersWithoutAccess(ctx context.Context, boardIds []int32) error {
+	_, err := q.db.Exec(ctx, unassignUsersWithoutAccess, boardIds)
+	return err
+}
+
+const updateBoard = `-- name: UpdateBoard :one
+UPDATE boards
+SET name = $1, description = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by
+`
+
+type UpdateBoardParams struct {
+	Name        string
+	Description pgtype.Text
+	ID          int32
+}
+
+func (q *Queries) UpdateBoard(ctx context.Context, arg UpdateBoardParams) (Board, error) {
+	row := q.db.QueryRow(ctx, updateBoard, arg.Name, arg.Description, arg.ID)
+	var i Board
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.Email,
-		&i.HashedPassword,
+		&i.Description,
+		&i.CreatedBy,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
-const moveCard = `-- name: MoveCard :one
-UPDATE cards
-SET list_id = $1, position = $2, updated_at = NOW()
-WHERE id = $3
-RETURNING id, title, description, list_id, position, created_at, updated_at
+const updateBoardMemberRole = `-- name: UpdateBoardMemberRole :one
+UPDATE board_members
+SET role = $1
+WHERE board_id = $2 AND user_id = $3
+RETURNING board_id, user_id, role, created_at
 `
 
-type MoveCardParams struct {
-	ListID   int32
-	Position int32
-	ID       int32
+type UpdateBoardMemberRoleParams struct {
+	Role    string
+	BoardID int32
+	UserID  int32
 }
 
-func (q *Queries) MoveCard(ctx context.Context, arg MoveCardParams) (Card, error) {
-	row := q.db.QueryRow(ctx, moveCard, arg.ListID, arg.Position, arg.ID)
-	var i Card
+func (q *Queries) UpdateBoardMemberRole(ctx context.Context, arg UpdateBoardMemberRoleParams) (BoardMember, error) {
+	row := q.db.QueryRow(ctx, updateBoardMemberRole, arg.Role, arg.BoardID, arg.UserID)
+	var i BoardMember
 	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Description,
-		&i.ListID,
-		&i.Position,
+		&i.BoardID,
+		&i.UserID,
+		&i.Role,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const updateBoard = `-- name: UpdateBoard :one
+const updateBoardWorkspace = `-- name: UpdateBoardWorkspace :one
 UPDATE boards
-SET name = $1, description = $2, updated_at = NOW()
+SET workspace_id = $1, visibility = $2, updated_at = NOW()
 WHERE id = $3
-RETURNING id, name, description, created_by, created_at, updated_at
+RETURNING id, name, description, created_by, created_at, updated_at, workspace_id, visibility, archived_at, deleted_at, deleted_by
 `
 
-type UpdateBoardParams struct {
-	Name        string
-	Description pgtype.Text
+type UpdateBoardWorkspaceParams struct {
+	WorkspaceID pgtype.Int4
+	Visibility  string
 	ID          int32
 }
 
-func (q *Queries) UpdateBoard(ctx context.Context, arg UpdateBoardParams) (Board, error) {
-	row := q.db.QueryRow(ctx, updateBoard, arg.Name, arg.Description, arg.ID)
+func (q *Queries) UpdateBoardWorkspace(ctx context.Context, arg UpdateBoardWorkspaceParams) (Board, error) {
+	row := q.db.QueryRow(ctx, updateBoardWorkspace, arg.WorkspaceID, arg.Visibility, arg.ID)
 	var i Board
 	err := row.Scan(
 		&i.ID,
@@ -429,32 +4211,96 @@ func (q *Queries) UpdateBoard(ctx context.Context, arg UpdateBoardParams) (Board
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
 const updateCard = `-- name: UpdateCard :one
 UPDATE cards
-SET title = $1, description = $2, updated_at = NOW()
-WHERE id = $3
-RETURNING id, title, description, list_id, position, created_at, updated_at
+SET title = $1, description = $2,
+  start_at = CASE WHEN $3::bool THEN $4 ELSE start_at END,
+  due_at = CASE WHEN $5::bool THEN $6 ELSE due_at END,
+  completed = CASE WHEN $7::bool THEN $8::bool ELSE completed END,
+  completed_at = CASE
+    WHEN NOT $7::bool THEN completed_at
+    WHEN $8::bool THEN COALESCE(completed_at, NOW())
+  END,
+  updated_at = NOW()
+WHERE id = $9
+RETURNING id, title, description, list_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by, start_at, due_at, completed, completed_at
 `
 
 type UpdateCardParams struct {
-	Title       string
-	Description pgtype.Text
-	ID          int32
+	Title        string
+	Description  pgtype.Text
+	SetStartAt   bool
+	StartAt      pgtype.Timestamptz
+	SetDueAt     bool
+	DueAt        pgtype.Timestamptz
+	SetCompleted bool
+	Completed    bool
+	ID           int32
 }
 
+// Dates and completion only change when their set_ flag is true; completing a card stamps completed_at once
 func (q *Queries) UpdateCard(ctx context.Context, arg UpdateCardParams) (Card, error) {
-	row := q.db.QueryRow(ctx, updateCard, arg.Title, arg.Description, arg.ID)
+	row := q.db.QueryRow(ctx, updateCard,
+		arg.Title,
+		arg.Description,
+		arg.SetStartAt,
+		arg.StartAt,
+		arg.SetDueAt,
+		arg.DueAt,
+		arg.SetCompleted,
+		arg.Completed,
+		arg.ID,
+	)
 	var i Card
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.Description,
 		&i.ListID,
-		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.StartAt,
+		&i.DueAt,
+		&i.Completed,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const updateLabel = `-- name: UpdateLabel :one
+UPDATE labels
+SET name = $1, color = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, board_id, name, color, created_at, updated_at
+`
+
+type UpdateLabelParams struct {
+	Name  string
+	Color string
+	ID    int32
+}
+
+func (q *Queries) UpdateLabel(ctx context.Context, arg UpdateLabelParams) (Label, error) {
+	row := q.db.QueryRow(ctx, updateLabel, arg.Name, arg.Color, arg.ID)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Name,
+		&i.Color,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -463,27 +4309,170 @@ func (q *Queries) UpdateCard(ctx context.Context, arg UpdateCardParams) (Card, e
 
 const updateList = `-- name: UpdateList :one
 UPDATE lists
-SET name = $1, position = $2, updated_at = NOW()
-where id = $3
-RETURNING id, name, board_id, position, created_at, updated_at
+SET name = $1, updated_at = NOW()
+where id = $2
+RETURNING id, name, board_id, created_at, updated_at, rank, archived_at, deleted_at, deleted_by
 `
 
 type UpdateListParams struct {
-	Name     string
-	Position int32
-	ID       int32
+	Name string
+	ID   int32
 }
 
 func (q *Queries) UpdateList(ctx context.Context, arg UpdateListParams) (List, error) {
-	row := q.db.QueryRow(ctx, updateList, arg.Name, arg.Position, arg.ID)
+	row := q.db.QueryRow(ctx, updateList, arg.Name, arg.ID)
 	var i List
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.BoardID,
-		&i.Position,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rank,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $1, has_password = TRUE
+WHERE id = $2
+`
+
+type UpdateUserPasswordParams struct {
+	HashedPassword string
+	ID             int32
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.HashedPassword, arg.ID)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET name = $1,
+    email = $2,
+    email_verified_at = CASE WHEN email = $2 THEN email_verified_at ELSE NULL END
+WHERE id = $3
+RETURNING id, name, email, hashed_password, created_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, has_password
+`
+
+type UpdateUserProfileParams struct {
+	Name  string
+	Email string
+	ID    int32
+}
+
+// Changing the email clears its verification
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.Name, arg.Email, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.HasPassword,
+	)
+	return i, err
+}
+
+const updateWorkspace = `-- name: UpdateWorkspace :one
+UPDATE workspaces
+SET name = $1, description = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, name, description, created_by, created_at, updated_at
+`
+
+type UpdateWorkspaceParams struct {
+	Name        string
+	Description pgtype.Text
+	ID          int32
+}
+
+func (q *Queries) UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRow(ctx, updateWorkspace, arg.Name, arg.Description, arg.ID)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceMemberRole = `-- name: UpdateWorkspaceMemberRole :one
+UPDATE workspace_members
+SET role = $1
+WHERE workspace_id = $2 AND user_id = $3
+RETURNING workspace_id, user_id, role, created_at
+`
+
+type UpdateWorkspaceMemberRoleParams struct {
+	Role        string
+	WorkspaceID int32
+	UserID      int32
+}
+
+func (q *Queries) UpdateWorkspaceMemberRole(ctx context.Context, arg UpdateWorkspaceMemberRoleParams) (WorkspaceMember, error) {
+	row := q.db.QueryRow(ctx, updateWorkspaceMemberRole, arg.Role, arg.WorkspaceID, arg.UserID)
+	var i WorkspaceMember
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
 	)
 	return i, err
 }
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   int32
+	CodeHash string
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE users
+SET totp_last_step = $2
+WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)
+`
+
+type UseTOTPStepParams struct {
+	ID           int32
+	TotpLastStep pgtype.Int8
+}
+
+// Only one login per time step, so an intercepted code cannot be replayed
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.ID, arg.TotpLastStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,86 @@
+// Package errs defines the kinds of errors the services return, so that
+// handlers can answer with the right HTTP status and a machine-readable code
+// without knowing every service's errors.
+package errs
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kind classifies an error
+type Kind int
+
+const (
+	// Internal is any unexpected failure, such as a lost database connection
+	Internal Kind = iota
+	// Validation means the input was rejected
+	Validation
+	// Unauthorized means the caller could not be authenticated
+	Unauthorized
+	// Forbidden means the caller may not perform the action
+	Forbidden
+	// NotFound means the resource does not exist
+	NotFound
+	// Conflict means the action clashes with the current state, like a duplicate email
+	Conflict
+	// Gone means the resource existed but is no longer usable, like an expired invitation
+	Gone
+)
+
+// Error is a domain error with a kind, a stable code for clients and a human-readable message
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Field names the rejected input field for validation errors
+	Field string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// New creates an Error
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Invalid creates a validation error for a field
+func Invalid(field, message string) *Error {
+	return &Error{Kind: Validation, Code: "invalid_" + field, Message: message, Field: field}
+}
+
+// KindOf returns the kind of the first Error in err's chain, or Internal if there is none
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Internal
+}
+
+// Postgres error codes for constraint violations
+const (
+	uniqueViolation = "23505"
+	checkViolation  = "23514"
+)
+
+// IsNoRows reports whether a query found no rows
+func IsNoRows(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows)
+}
+
+// IsUniqueViolation reports whether a query broke a unique constraint
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// IsCheckViolation reports whether a query broke the named check constraint
+func IsCheckViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == checkViolation && pgErr.ConstraintName == constraint
+}
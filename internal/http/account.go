@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/account"
+)
+
+// AccountHandler handles data export and account deletion
+type AccountHandler struct {
+	service *account.Service
+	sm      *scs.SessionManager
+}
+
+// NewAccountHandler creates a new AccountHandler
+func NewAccountHandler(service *account.Service, sm *scs.SessionManager) *AccountHandler {
+	return &AccountHandler{
+		service: service,
+		sm:      sm,
+	}
+}
+
+// RegisterRoutes adds the account routes to router
+func (h *AccountHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	// Both routes deal with the whole account, so tokens need the admin scope
+	mux.Handle("GET /api/me/export", mw.RequireAuthUnverified(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleExport))))
+	mux.Handle("DELETE /api/me", mw.RequireAuthUnverified(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDeleteAccount))))
+}
+
+// DeleteAccountRequest confirms the deletion and names the new owners of shared boards and workspaces
+type DeleteAccountRequest struct {
+	Password           string          `json:"password" validate:"max=1024"`
+	BoardTransfers     map[int32]int32 `json:"board_transfers"`
+	WorkspaceTransfers map[int32]int32 `json:"workspace_transfers"`
+}
+
+// handleExport downloads the user's personal data.
+// It is a ZIP archive of JSON files by default, or a single JSON document with ?format=json.
+func (h *AccountHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "zip" && format != "json" {
+		WriteError(w, http.StatusBadRequest, "format must be zip or json")
+		return
+	}
+
+	export, err := h.service.Export(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("goboard-export-%d-%s", user.ID, export.ExportedAt.Format("20060102"))
+	if format == "json" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		WriteJSON(w, http.StatusOK, export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	if err := export.WriteZip(w); err != nil {
+		// The headers are already sent, so the broken download is all the client gets
+		log.Printf("Failed to write export for user %d: %v", user.ID, err)
+	}
+}
+
+// handleDeleteAccount deletes the authenticated user's account and ends the current session
+func (h *AccountHandler) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.service.Delete(r.Context(), user.ID, req.Password, account.Transfers{
+		Boards:     req.BoardTransfers,
+		Workspaces: req.WorkspaceTransfers,
+	})
+	if err != nil {
+		var ownership *account.OwnershipError
+		if errors.As(err, &ownership) {
+			WriteJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":      ownership.Error(),
+				"code":       "owned_resources",
+				"boards":     ownership.Boards,
+				"workspaces": ownership.Workspaces,
+			})
+			return
+		}
+		WriteServiceError(w, err)
+		return
+	}
+
+	// The session row is already gone; this clears the cookie
+	if err := h.sm.Destroy(r.Context()); err != nil {
+		log.Printf("Failed to destroy session after deleting user %d: %v", user.ID, err)
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Account deleted"})
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+)
+
+// handleGetAssignees gets the users assigned to a card
+func (h *CardHandler) handleGetAssignees(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	// Get card assignees
+	assignees, err := h.service.GetAssignees(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, assignees)
+}
+
+// handleAssign assigns a user to a card
+func (h *CardHandler) handleAssign(w http.ResponseWriter, r *http.Request) {
+	h.setAssigned(w, r, true)
+}
+
+// handleUnassign removes a user from a card's assignees
+func (h *CardHandler) handleUnassign(w http.ResponseWriter, r *http.Request) {
+	h.setAssigned(w, r, false)
+}
+
+// setAssigned assigns or unassigns the user in the URL and answers with the card's assignees
+func (h *CardHandler) setAssigned(w http.ResponseWriter, r *http.Request, assign bool) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card and user IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	assigneeID, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var assignees []db.GetCardAssigneesRow
+	if assign {
+		assignees, err = h.service.Assign(r.Context(), user.ID, int32(id), int32(assigneeID))
+	} else {
+		assignees, err = h.service.Unassign(r.Context(), user.ID, int32(id), int32(assigneeID))
+	}
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, assignees)
+}
+
+// handleGetAssignedCards gets the cards assigned to the authenticated user, grouped by board and list
+func (h *CardHandler) handleGetAssignedCards(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	boards, err := h.service.GetAssignedCards(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, boards)
+}
@@ -1,21 +1,21 @@
 package http
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
 	"github.com/anubhav047/goboard/internal/db"
-	"github.com/anubhav047/goboard/internal/services/board"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	boardservice "github.com/anubhav047/goboard/internal/services/board"
 )
 
 // BoardHandler handles HTTP requests for boards
 type BoardHandler struct {
-	service *board.Service
+	service *boardservice.Service
 }
 
 // NewBoardHandler creates a new BoardHandler
-func NewBoardHandler(service *board.Service) *BoardHandler {
+func NewBoardHandler(service *boardservice.Service) *BoardHandler {
 	return &BoardHandler{
 		service: service,
 	}
@@ -25,20 +25,43 @@ func NewBoardHandler(service *board.Service) *BoardHandler {
 func (h *BoardHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
 	// All Board routes require authentication
 	mux.Handle("GET /api/boards", mw.RequireAuth((http.HandlerFunc(h.handleGetUserBoards))))
-	mux.Handle("POST /api/boards", mw.RequireAuth(http.HandlerFunc(h.handleCreateBoard)))
+	mux.Handle("POST /api/boards", mw.RequireAuth(mw.RequireVerified(http.HandlerFunc(h.handleCreateBoard))))
 	mux.Handle("GET /api/boards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleGetBoard)))
+	mux.Handle("GET /api/boards/{id}/full", mw.RequireAuth(http.HandlerFunc(h.handleGetBoardSnapshot)))
 	mux.Handle("PUT /api/boards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateBoard)))
-	mux.Handle("DELETE /api/boards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleDeleteBoard)))
+	mux.Handle("DELETE /api/boards/{id}", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDeleteBoard))))
+	mux.Handle("POST /api/boards/{id}/archive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleArchiveBoard))))
+	mux.Handle("POST /api/boards/{id}/unarchive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleUnarchiveBoard))))
+	mux.Handle("PUT /api/boards/{id}/workspace", mw.RequireAuth(http.HandlerFunc(h.handleSetBoardWorkspace)))
+
+	// Board label routes
+	mux.Handle("GET /api/boards/{id}/labels", mw.RequireAuth(http.HandlerFunc(h.handleGetLabels)))
+	mux.Handle("POST /api/boards/{id}/labels", mw.RequireAuth(http.HandlerFunc(h.handleCreateLabel)))
+	mux.Handle("PUT /api/boards/{id}/labels/{labelId}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateLabel)))
+	mux.Handle("DELETE /api/boards/{id}/labels/{labelId}", mw.RequireAuth(http.HandlerFunc(h.handleDeleteLabel)))
+
+	// Board membership routes
+	mux.Handle("GET /api/boards/{id}/members", mw.RequireAuth(http.HandlerFunc(h.handleGetMembers)))
+	mux.Handle("POST /api/boards/{id}/members", mw.RequireAuth(http.HandlerFunc(h.handleAddMember)))
+	mux.Handle("PUT /api/boards/{id}/members/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateMember)))
+	mux.Handle("DELETE /api/boards/{id}/members/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleRemoveMember)))
 }
 
 type CreateBoardRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	WorkspaceID int32  `json:"workspace_id" validate:"min=0"`
+	Visibility  string `json:"visibility" validate:"oneof=private workspace"`
 }
 
 type UpdateBoardRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+}
+
+type SetBoardWorkspaceRequest struct {
+	WorkspaceID int32  `json:"workspace_id" validate:"min=0"`
+	Visibility  string `json:"visibility" validate:"oneof=private workspace"`
 }
 
 // handleCreateBoard creates a new board
@@ -52,15 +75,14 @@ func (h *BoardHandler) handleCreateBoard(w http.ResponseWriter, r *http.Request)
 
 	// Parse request body
 	var req CreateBoardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid Request Payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Create Board
-	board, err := h.service.CreateBoard(r.Context(), req.Name, req.Description, user.ID)
+	board, err := h.service.CreateBoard(r.Context(), req.Name, req.Description, user.ID, req.WorkspaceID, req.Visibility)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -68,7 +90,7 @@ func (h *BoardHandler) handleCreateBoard(w http.ResponseWriter, r *http.Request)
 	WriteJSON(w, http.StatusCreated, board)
 }
 
-// handleGetUserBoards gets all boards for the authenticated user
+// handleGetUserBoards gets all boards the authenticated user is a member of
 func (h *BoardHandler) handleGetUserBoards(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
 	user, ok := r.Context().Value(userContextKey).(db.User)
@@ -77,10 +99,15 @@ func (h *BoardHandler) handleGetUserBoards(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	archived, ok := archivedParam(w, r)
+	if !ok {
+		return
+	}
+
 	// Get user's boards
-	boards, err := h.service.GetUserBoards(r.Context(), user.ID)
+	boards, err := h.service.GetUserBoards(r.Context(), user.ID, archived)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -89,6 +116,13 @@ func (h *BoardHandler) handleGetUserBoards(w http.ResponseWriter, r *http.Reques
 
 // handleGetBoard gets a single board by ID
 func (h *BoardHandler) handleGetBoard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse board ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -98,17 +132,63 @@ func (h *BoardHandler) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get board
-	board, err := h.service.GetBoardByID(r.Context(), int32(id))
+	board, err := h.service.GetBoardByID(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "Board not found")
+		WriteServiceError(w, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, board)
 }
 
+// handleGetBoardSnapshot gets a board with its lists and cards in one response.
+// ?include=labels,assignees,members embeds the board labels, card labels and assignees, and board members.
+func (h *BoardHandler) handleGetBoardSnapshot(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	include, err := boardservice.ParseInclude(r.URL.Query().Get("include"))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	labelIDs, ok := labelsParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Get board snapshot
+	snapshot, err := h.service.GetSnapshot(r.Context(), user.ID, int32(id), include, labelIDs)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, snapshot)
+}
+
 // handleUpdateBoard updates a board
 func (h *BoardHandler) handleUpdateBoard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse board ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -119,23 +199,98 @@ func (h *BoardHandler) handleUpdateBoard(w http.ResponseWriter, r *http.Request)
 
 	// Parse request body
 	var req UpdateBoardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Update board
-	board, err := h.service.UpdateBoard(r.Context(), int32(id), req.Name, req.Description)
+	board, err := h.service.UpdateBoard(r.Context(), user.ID, int32(id), req.Name, req.Description)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, board)
+}
+
+// handleSetBoardWorkspace moves a board into or out of a workspace
+func (h *BoardHandler) handleSetBoardWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Parse request body
+	var req SetBoardWorkspaceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Move board
+	board, err := h.service.SetWorkspace(r.Context(), user.ID, int32(id), req.WorkspaceID, req.Visibility)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, board)
 }
 
-// handleDeleteBoard deletes a board
+// handleArchiveBoard archives a board
+func (h *BoardHandler) handleArchiveBoard(w http.ResponseWriter, r *http.Request) {
+	h.setBoardArchived(w, r, true)
+}
+
+// handleUnarchiveBoard restores an archived board
+func (h *BoardHandler) handleUnarchiveBoard(w http.ResponseWriter, r *http.Request) {
+	h.setBoardArchived(w, r, false)
+}
+
+// setBoardArchived archives or restores the board in the URL
+func (h *BoardHandler) setBoardArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	board, err := h.service.SetArchived(r.Context(), user.ID, int32(id), archived)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, board)
+}
+
+// handleDeleteBoard moves an archived board to the trash
 func (h *BoardHandler) handleDeleteBoard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse board ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -145,9 +300,9 @@ func (h *BoardHandler) handleDeleteBoard(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Delete board
-	err = h.service.DeleteBoard(r.Context(), int32(id))
+	err = h.service.DeleteBoard(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
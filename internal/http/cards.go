@@ -1,11 +1,13 @@
 package http
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
 	"github.com/anubhav047/goboard/internal/services/card"
+	"github.com/anubhav047/goboard/internal/validate"
 )
 
 // CardHandler handles HTTP requests for cards
@@ -28,27 +30,56 @@ func (h *CardHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
 	mux.Handle("GET /api/cards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleGetCard)))
 	mux.Handle("PUT /api/cards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateCard)))
 	mux.Handle("PUT /api/cards/{id}/move", mw.RequireAuth(http.HandlerFunc(h.handleMoveCard)))
-	mux.Handle("DELETE /api/cards/{id}", mw.RequireAuth(http.HandlerFunc(h.handleDeleteCard)))
+	mux.Handle("DELETE /api/cards/{id}", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDeleteCard))))
+	mux.Handle("POST /api/cards/{id}/archive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleArchiveCard))))
+	mux.Handle("POST /api/cards/{id}/unarchive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleUnarchiveCard))))
+	mux.Handle("PUT /api/cards/{id}/labels/{labelId}", mw.RequireAuth(http.HandlerFunc(h.handleAttachLabel)))
+	mux.Handle("DELETE /api/cards/{id}/labels/{labelId}", mw.RequireAuth(http.HandlerFunc(h.handleDetachLabel)))
+	mux.Handle("GET /api/cards/{id}/assignees", mw.RequireAuth(http.HandlerFunc(h.handleGetAssignees)))
+	mux.Handle("PUT /api/cards/{id}/assignees/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleAssign)))
+	mux.Handle("DELETE /api/cards/{id}/assignees/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleUnassign)))
+	mux.Handle("GET /api/cards/{id}/reminders", mw.RequireAuth(http.HandlerFunc(h.handleGetReminders)))
+	mux.Handle("POST /api/cards/{id}/reminders", mw.RequireAuth(http.HandlerFunc(h.handleAddReminder)))
+	mux.Handle("DELETE /api/cards/{id}/reminders/{reminderId}", mw.RequireAuth(http.HandlerFunc(h.handleDeleteReminder)))
+	mux.Handle("GET /api/me/cards", mw.RequireAuth(http.HandlerFunc(h.handleGetAssignedCards)))
+	mux.Handle("GET /api/me/cards/due-soon", mw.RequireAuth(http.HandlerFunc(h.handleGetDueSoon)))
+	mux.Handle("GET /api/me/cards/overdue", mw.RequireAuth(http.HandlerFunc(h.handleGetOverdue)))
 }
 
 type CreateCardRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Position    int32  `json:"position"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+	AfterID     int32  `json:"after_id" validate:"min=0"`
+	BeforeID    int32  `json:"before_id" validate:"min=0"`
 }
 
+// UpdateCardRequest replaces the title and description of a card.
+// Dates and completion are only changed when present; a null date clears it.
 type UpdateCardRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string       `json:"title" validate:"required,max=255"`
+	Description string       `json:"description" validate:"max=10000"`
+	StartAt     NullableTime `json:"start_at"`
+	DueAt       NullableTime `json:"due_at"`
+	Completed   *bool        `json:"completed"`
 }
 
+// MoveCardRequest places the card right after the card AfterID and before the card BeforeID.
+// Either may be omitted; with neither the card goes to the end of the list.
 type MoveCardRequest struct {
-	ListID   int32 `json:"list_id"`
-	Position int32 `json:"position"`
+	ListID   int32 `json:"list_id" validate:"required,min=1"`
+	AfterID  int32 `json:"after_id" validate:"min=0"`
+	BeforeID int32 `json:"before_id" validate:"min=0"`
 }
 
 // handleCreateCard creates a new card in a list
 func (h *CardHandler) handleCreateCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse list ID from URL
 	listIdStr := r.PathValue("listId")
 	listId, err := strconv.ParseInt(listIdStr, 10, 32)
@@ -59,15 +90,14 @@ func (h *CardHandler) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req CreateCardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Create card
-	card, err := h.service.CreateCard(r.Context(), req.Title, req.Description, int32(listId), req.Position)
+	card, err := h.service.CreateCard(r.Context(), user.ID, req.Title, req.Description, int32(listId), req.AfterID, req.BeforeID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -76,6 +106,13 @@ func (h *CardHandler) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 
 // handleGetListCards gets all cards for a list
 func (h *CardHandler) handleGetListCards(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse list ID from URL
 	listIdStr := r.PathValue("listId")
 	listId, err := strconv.ParseInt(listIdStr, 10, 32)
@@ -84,10 +121,20 @@ func (h *CardHandler) handleGetListCards(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	archived, ok := archivedParam(w, r)
+	if !ok {
+		return
+	}
+
+	labelIDs, ok := labelsParam(w, r)
+	if !ok {
+		return
+	}
+
 	// Get list's cards
-	cards, err := h.service.GetListCards(r.Context(), int32(listId))
+	cards, err := h.service.GetListCards(r.Context(), user.ID, int32(listId), archived, labelIDs)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -96,6 +143,13 @@ func (h *CardHandler) handleGetListCards(w http.ResponseWriter, r *http.Request)
 
 // handleGetCard gets a single card by ID
 func (h *CardHandler) handleGetCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse card ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -105,9 +159,9 @@ func (h *CardHandler) handleGetCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get card
-	card, err := h.service.GetCardByID(r.Context(), int32(id))
+	card, err := h.service.GetCardByID(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "Card not found")
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -116,6 +170,13 @@ func (h *CardHandler) handleGetCard(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateCard updates a card's title and description
 func (h *CardHandler) handleUpdateCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse card ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -126,15 +187,25 @@ func (h *CardHandler) handleUpdateCard(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req UpdateCardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	fields := validate.Errors{}
+	schedule := card.Schedule{
+		StartAt:   parseTime(fields, "start_at", req.StartAt),
+		DueAt:     parseTime(fields, "due_at", req.DueAt),
+		Completed: req.Completed,
+	}
+	if len(fields) > 0 {
+		writeValidationError(w, fields)
 		return
 	}
 
 	// Update card
-	card, err := h.service.UpdateCard(r.Context(), int32(id), req.Title, req.Description)
+	card, err := h.service.UpdateCard(r.Context(), user.ID, int32(id), req.Title, req.Description, schedule)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -143,6 +214,13 @@ func (h *CardHandler) handleUpdateCard(w http.ResponseWriter, r *http.Request) {
 
 // handleMoveCard moves a card to a different list and/or position (for drag & drop)
 func (h *CardHandler) handleMoveCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse card ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -153,23 +231,65 @@ func (h *CardHandler) handleMoveCard(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req MoveCardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Move card
-	card, err := h.service.MoveCard(r.Context(), int32(id), req.ListID, req.Position)
+	card, err := h.service.MoveCard(r.Context(), user.ID, int32(id), req.ListID, req.AfterID, req.BeforeID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, card)
 }
 
-// handleDeleteCard deletes a card
+// handleArchiveCard archives a card
+func (h *CardHandler) handleArchiveCard(w http.ResponseWriter, r *http.Request) {
+	h.setCardArchived(w, r, true)
+}
+
+// handleUnarchiveCard restores an archived card
+func (h *CardHandler) handleUnarchiveCard(w http.ResponseWriter, r *http.Request) {
+	h.setCardArchived(w, r, false)
+}
+
+// setCardArchived archives or restores the card in the URL
+func (h *CardHandler) setCardArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.service.SetArchived(r.Context(), user.ID, int32(id), archived)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, card)
+}
+
+// handleDeleteCard moves an archived card to the trash
 func (h *CardHandler) handleDeleteCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse card ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -179,9 +299,9 @@ func (h *CardHandler) handleDeleteCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete card
-	err = h.service.DeleteCard(r.Context(), int32(id))
+	err = h.service.DeleteCard(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
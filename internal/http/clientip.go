@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+var trustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and CIDR ranges,
+// like "10.0.0.0/8, 192.168.1.10"
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy range %q", entry)
+			}
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q", entry)
+		}
+		addr = addr.Unmap()
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return proxies, nil
+}
+
+// SetTrustedProxies sets the reverse proxies whose X-Forwarded-For header is believed.
+// By default no proxy is trusted and the header is ignored. Call it before serving requests.
+func SetTrustedProxies(proxies []netip.Prefix) {
+	trustedProxies = proxies
+}
+
+// clientIP returns the IP address of the client that sent the request.
+// Behind a trusted proxy it is the last X-Forwarded-For entry that is not a trusted
+// proxy itself; the entries before it were sent by the client and can be forged.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isTrustedProxy(addr) {
+		return host
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// A proxy we trust sent a malformed chain, so the proxy is the best we know
+			return host
+		}
+		addr = hop.Unmap()
+		if !isTrustedProxy(addr) {
+			return addr.String()
+		}
+	}
+
+	// Every hop is a trusted proxy, so the first one is where the request came from
+	return addr.String()
+}
+
+// isTrustedProxy reports whether the address belongs to a trusted proxy
+func isTrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/validate"
+)
+
+// DecodeOptions controls how JSON request bodies are read
+type DecodeOptions struct {
+	// MaxBodyBytes is the largest request body accepted
+	MaxBodyBytes int64
+	// DisallowUnknownFields rejects bodies with fields the request type does not have
+	DisallowUnknownFields bool
+}
+
+// DefaultDecodeOptions accepts bodies up to 1 MB and rejects unknown fields
+func DefaultDecodeOptions() DecodeOptions {
+	return DecodeOptions{
+		MaxBodyBytes:          1 << 20,
+		DisallowUnknownFields: true,
+	}
+}
+
+var decodeOptions = DefaultDecodeOptions()
+
+// SetDecodeOptions changes how request bodies are read. Call it before serving requests.
+func SetDecodeOptions(opts DecodeOptions) {
+	decodeOptions = opts
+}
+
+// decodeJSON reads a single JSON object from the request body into dst and validates it.
+// When the body is rejected it writes the error response and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, decodeOptions.MaxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	if decodeOptions.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(dst); err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+
+	// Anything after the object is garbage
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			writeDecodeError(w, err)
+			return false
+		}
+		WriteError(w, http.StatusBadRequest, "Request body must contain a single JSON object")
+		return false
+	}
+
+	if err := validate.Struct(dst); err != nil {
+		var fields validate.Errors
+		if errors.As(err, &fields) {
+			writeValidationError(w, fields)
+			return false
+		}
+		WriteServiceError(w, err)
+		return false
+	}
+
+	return true
+}
+
+// writeDecodeError answers a body that could not be decoded
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &maxBytes):
+		WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		writeValidationError(w, validate.Errors{typeErr.Field: "must be " + jsonType(typeErr.Type)})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no error type for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		writeValidationError(w, validate.Errors{field: "is not a known field"})
+	default:
+		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	}
+}
+
+// writeValidationError answers with the problem of each rejected field
+func writeValidationError(w http.ResponseWriter, fields validate.Errors) {
+	WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":  "Invalid request",
+		"code":   "validation_failed",
+		"fields": fields,
+	})
+}
+
+// jsonType describes the JSON value expected for a Go type
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number in range"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// NullableTime is an optional timestamp in a request body. It tells a missing field,
+// which leaves the value unchanged, apart from null, which clears it.
+type NullableTime struct {
+	// Set is true when the field was present, even if it was null
+	Set bool
+	// Value is the raw timestamp, empty for null
+	Value string
+}
+
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Value = ""
+		return nil
+	}
+	return json.Unmarshal(data, &t.Value)
+}
+
+// parseTime reads a NullableTime, recording a problem with it in fields.
+// It returns nil when the field is missing and the zero time when it is null.
+// Timestamps must be RFC 3339 with a time zone and are converted to UTC.
+func parseTime(fields validate.Errors, name string, t NullableTime) *time.Time {
+	if !t.Set {
+		return nil
+	}
+	if t.Value == "" {
+		return &time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, t.Value)
+	if err != nil {
+		fields[name] = "must be a timestamp with a time zone, like 2026-01-02T15:04:05Z"
+		return nil
+	}
+	parsed = parsed.UTC()
+	return &parsed
+}
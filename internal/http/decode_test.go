@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/validate"
+)
+
+func TestNullableTime(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want NullableTime
+	}{
+		{name: "missing", body: `{}`, want: NullableTime{}},
+		{name: "null", body: `{"due_at": null}`, want: NullableTime{Set: true}},
+		{name: "timestamp", body: `{"due_at": "2026-01-02T15:04:05Z"}`, want: NullableTime{Set: true, Value: "2026-01-02T15:04:05Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req struct {
+				DueAt NullableTime `json:"due_at"`
+			}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+			if req.DueAt != tt.want {
+				t.Errorf("NullableTime = %+v, want %+v", req.DueAt, tt.want)
+			}
+		})
+	}
+
+	var req struct {
+		DueAt NullableTime `json:"due_at"`
+	}
+	if err := json.Unmarshal([]byte(`{"due_at": 1767366245}`), &req); err == nil {
+		t.Error("NullableTime accepted a number")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   NullableTime
+		want    *time.Time
+		invalid bool
+	}{
+		{name: "missing", value: NullableTime{}, want: nil},
+		{name: "null clears", value: NullableTime{Set: true}, want: &time.Time{}},
+		{name: "utc", value: NullableTime{Set: true, Value: "2026-01-02T15:04:05Z"}, want: ptrTime(time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC))},
+		{name: "offset converted to utc", value: NullableTime{Set: true, Value: "2026-01-02T15:04:05+05:30"}, want: ptrTime(time.Date(2026, 1, 2, 9, 34, 5, 0, time.UTC))},
+		{name: "offset across midnight", value: NullableTime{Set: true, Value: "2026-01-01T20:00:00-08:00"}, want: ptrTime(time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC))},
+		{name: "fractional seconds", value: NullableTime{Set: true, Value: "2026-01-02T15:04:05.5Z"}, want: ptrTime(time.Date(2026, 1, 2, 15, 4, 5, 500000000, time.UTC))},
+		{name: "no time zone", value: NullableTime{Set: true, Value: "2026-01-02T15:04:05"}, invalid: true},
+		{name: "date only", value: NullableTime{Set: true, Value: "2026-01-02"}, invalid: true},
+		{name: "garbage", value: NullableTime{Set: true, Value: "tomorrow"}, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validate.Errors{}
+			got := parseTime(fields, "due_at", tt.value)
+
+			if tt.invalid {
+				if got != nil || fields["due_at"] == "" {
+					t.Errorf("parseTime = %v with errors %v, want a due_at error", got, fields)
+				}
+				return
+			}
+			if len(fields) != 0 {
+				t.Fatalf("parseTime recorded errors %v", fields)
+			}
+
+			switch {
+			case tt.want == nil:
+				if got != nil {
+					t.Errorf("parseTime = %v, want nil", got)
+				}
+			case got == nil:
+				t.Errorf("parseTime = nil, want %v", tt.want)
+			case !got.Equal(*tt.want) || got.Location() != time.UTC:
+				t.Errorf("parseTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/card"
+)
+
+// handleGetDueSoon gets the open cards due soon on the authenticated user's boards.
+// The within query parameter sets how soon, like 48h, and defaults to a day.
+func (h *CardHandler) handleGetDueSoon(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	within := 24 * time.Hour
+	if v := r.URL.Query().Get("within"); v != "" {
+		var err error
+		within, err = time.ParseDuration(v)
+		if err != nil {
+			WriteServiceError(w, card.ErrInvalidWindow)
+			return
+		}
+	}
+
+	cards, err := h.service.GetDueSoon(r.Context(), user.ID, within)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, cards)
+}
+
+// handleGetOverdue gets the open cards past their due date on the authenticated user's boards
+func (h *CardHandler) handleGetOverdue(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	cards, err := h.service.GetOverdue(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, cards)
+}
@@ -2,7 +2,15 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/loginguard"
 )
 
 // WriteJSON encodes the data into JSON, sets the content-type header, and writes the response.
@@ -12,7 +20,103 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-// WriteError sends a structured JSON error message
+// WriteError sends a structured JSON error message.
+// The code is derived from the status, like "not_found" for 404.
 func WriteError(w http.ResponseWriter, status int, message string) {
-	WriteJSON(w, status, map[string]string{"error": message})
+	WriteJSON(w, status, map[string]string{"error": message, "code": statusCode(status)})
+}
+
+// WriteServiceError maps errors returned by the services to an HTTP status code.
+// Domain errors answer with their own status and code; anything else is logged and hidden behind a 500.
+func WriteServiceError(w http.ResponseWriter, err error) {
+	var e *errs.Error
+	if !errors.As(err, &e) || e.Kind == errs.Internal {
+		log.Printf("Unexpected error: %v", err)
+		WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "An unexpected error occurred", "code": statusCode(http.StatusInternalServerError)})
+		return
+	}
+
+	// Rejected fields are reported the same way as request validation errors
+	if e.Kind == errs.Validation && e.Field != "" {
+		WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":  e.Message,
+			"code":   e.Code,
+			"fields": map[string]string{e.Field: e.Message},
+		})
+		return
+	}
+	WriteJSON(w, kindStatus[e.Kind], map[string]string{"error": e.Message, "code": e.Code})
+}
+
+// kindStatus maps error kinds to HTTP status codes
+var kindStatus = map[errs.Kind]int{
+	errs.Validation:   http.StatusBadRequest,
+	errs.Unauthorized: http.StatusUnauthorized,
+	errs.Forbidden:    http.StatusForbidden,
+	errs.NotFound:     http.StatusNotFound,
+	errs.Conflict:     http.StatusConflict,
+	errs.Gone:         http.StatusGone,
+}
+
+// statusCode turns an HTTP status into a machine-readable code, like "bad_request"
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// writeGuardError answers a throttled login with 429 and a Retry-After header
+func writeGuardError(w http.ResponseWriter, err error) {
+	var throttled *loginguard.ThrottledError
+	if errors.As(err, &throttled) {
+		seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		WriteError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	log.Printf("Failed to check login attempts: %v", err)
+	WriteError(w, http.StatusInternalServerError, "An unexpected error occurred")
+}
+
+// loginAttempt describes a login attempt for the login guard
+func loginAttempt(r *http.Request, email string) loginguard.Attempt {
+	return loginguard.Attempt{
+		Email:     email,
+		IPAddress: clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// archivedParam reads the archived query parameter, which selects archived items instead of active ones.
+// When the value is not a boolean it writes the error response and returns false.
+func archivedParam(w http.ResponseWriter, r *http.Request) (archived bool, ok bool) {
+	value := r.URL.Query().Get("archived")
+	if value == "" {
+		return false, true
+	}
+
+	archived, err := strconv.ParseBool(value)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "archived must be true or false")
+		return false, false
+	}
+	return archived, true
+}
+
+// labelsParam reads the labels query parameter, a comma-separated list of label IDs to filter cards by.
+// When an ID is invalid it writes the error response and returns false.
+func labelsParam(w http.ResponseWriter, r *http.Request) (labelIDs []int32, ok bool) {
+	labelIDs = []int32{}
+	for _, s := range strings.Split(r.URL.Query().Get("labels"), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "labels must be a comma-separated list of label IDs")
+			return nil, false
+		}
+		labelIDs = append(labelIDs, int32(id))
+	}
+	return labelIDs, true
 }
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/invitation"
+)
+
+// InvitationHandler handles HTTP requests for board invitations
+type InvitationHandler struct {
+	service *invitation.Service
+}
+
+// NewInvitationHandler creates a new InvitationHandler
+func NewInvitationHandler(service *invitation.Service) *InvitationHandler {
+	return &InvitationHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes adds the invitation routes to router
+func (h *InvitationHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	// All invitation routes require authentication
+	mux.Handle("GET /api/boards/{id}/invitations", mw.RequireAuth(http.HandlerFunc(h.handleGetBoardInvitations)))
+	mux.Handle("POST /api/boards/{id}/invitations", mw.RequireAuth(http.HandlerFunc(h.handleCreateInvitation)))
+	mux.Handle("DELETE /api/boards/{id}/invitations/{invitationId}", mw.RequireAuth(http.HandlerFunc(h.handleRevokeInvitation)))
+	mux.Handle("GET /api/me/invitations", mw.RequireAuth(http.HandlerFunc(h.handleGetMyInvitations)))
+	mux.Handle("POST /api/invitations/accept", mw.RequireAuth(http.HandlerFunc(h.handleAcceptInvitation)))
+	mux.Handle("POST /api/invitations/decline", mw.RequireAuth(http.HandlerFunc(h.handleDeclineInvitation)))
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,max=255"`
+	Role  string `json:"role" validate:"oneof=admin editor viewer"`
+}
+
+// RespondInvitationRequest identifies an invitation by its emailed token,
+// or by ID when it is already attached to the user's account
+type RespondInvitationRequest struct {
+	Token string `json:"token" validate:"max=512"`
+	ID    int32  `json:"id" validate:"min=0"`
+}
+
+// handleCreateInvitation invites someone to a board by email
+func (h *InvitationHandler) handleCreateInvitation(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Parse request body
+	var req CreateInvitationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Create and send invitation
+	inv, err := h.service.Invite(r.Context(), user.ID, int32(id), req.Email, req.Role)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, invitationResponse(inv))
+}
+
+// handleGetBoardInvitations gets all invitations sent for a board
+func (h *InvitationHandler) handleGetBoardInvitations(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Get board invitations
+	invitations, err := h.service.GetBoardInvitations(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, invitations)
+}
+
+// handleRevokeInvitation revokes a pending invitation
+func (h *InvitationHandler) handleRevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board and invitation IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+	invitationID, err := strconv.ParseInt(r.PathValue("invitationId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	// Revoke invitation
+	err = h.service.Revoke(r.Context(), user.ID, int32(id), int32(invitationID))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Invitation revoked successfully"})
+}
+
+// handleGetMyInvitations gets the pending invitations for the authenticated user
+func (h *InvitationHandler) handleGetMyInvitations(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	invitations, err := h.service.GetUserInvitations(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, invitations)
+}
+
+// handleAcceptInvitation accepts an invitation and joins the board
+func (h *InvitationHandler) handleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse request body
+	var req RespondInvitationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	inv, err := h.service.Accept(r.Context(), user, req.Token, req.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, invitationResponse(inv))
+}
+
+// handleDeclineInvitation declines an invitation
+func (h *InvitationHandler) handleDeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse request body
+	var req RespondInvitationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	inv, err := h.service.Decline(r.Context(), user, req.Token, req.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, invitationResponse(inv))
+}
+
+// invitationResponse builds the invitation payload, leaving out the token hash
+func invitationResponse(inv *db.BoardInvitation) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           inv.ID,
+		"board_id":     inv.BoardID,
+		"email":        inv.Email,
+		"role":         inv.Role,
+		"invited_by":   inv.InvitedBy,
+		"status":       inv.Status,
+		"expires_at":   inv.ExpiresAt,
+		"responded_at": inv.RespondedAt,
+		"created_at":   inv.CreatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+)
+
+type LabelRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Color string `json:"color" validate:"required,max=7"`
+}
+
+// handleGetLabels gets the labels of a board
+func (h *BoardHandler) handleGetLabels(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Get board labels
+	labels, err := h.service.GetLabels(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, labels)
+}
+
+// handleCreateLabel adds a label to a board
+func (h *BoardHandler) handleCreateLabel(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Parse request body
+	var req LabelRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Create label
+	label, err := h.service.CreateLabel(r.Context(), user.ID, int32(id), req.Name, req.Color)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, label)
+}
+
+// handleUpdateLabel changes the name and color of a board label
+func (h *BoardHandler) handleUpdateLabel(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board and label IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+	labelID, err := strconv.ParseInt(r.PathValue("labelId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid label ID")
+		return
+	}
+
+	// Parse request body
+	var req LabelRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Update label
+	label, err := h.service.UpdateLabel(r.Context(), user.ID, int32(id), int32(labelID), req.Name, req.Color)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, label)
+}
+
+// handleDeleteLabel deletes a board label and removes it from its cards
+func (h *BoardHandler) handleDeleteLabel(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board and label IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+	labelID, err := strconv.ParseInt(r.PathValue("labelId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid label ID")
+		return
+	}
+
+	// Delete label
+	err = h.service.DeleteLabel(r.Context(), user.ID, int32(id), int32(labelID))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Label deleted successfully"})
+}
+
+// handleAttachLabel adds a label to a card
+func (h *CardHandler) handleAttachLabel(w http.ResponseWriter, r *http.Request) {
+	h.setCardLabel(w, r, true)
+}
+
+// handleDetachLabel removes a label from a card
+func (h *CardHandler) handleDetachLabel(w http.ResponseWriter, r *http.Request) {
+	h.setCardLabel(w, r, false)
+}
+
+// setCardLabel attaches or detaches the label in the URL and answers with the card's labels
+func (h *CardHandler) setCardLabel(w http.ResponseWriter, r *http.Request, attach bool) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card and label IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	labelID, err := strconv.ParseInt(r.PathValue("labelId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid label ID")
+		return
+	}
+
+	var labels []db.Label
+	if attach {
+		labels, err = h.service.AttachLabel(r.Context(), user.ID, int32(id), int32(labelID))
+	} else {
+		labels, err = h.service.DetachLabel(r.Context(), user.ID, int32(id), int32(labelID))
+	}
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, labels)
+}
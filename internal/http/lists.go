@@ -1,10 +1,11 @@
 package http
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
 	"github.com/anubhav047/goboard/internal/services/list"
 )
 
@@ -27,21 +28,39 @@ func (h *ListHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
 	mux.Handle("POST /api/boards/{boardId}/lists", mw.RequireAuth(http.HandlerFunc(h.handleCreateList)))
 	mux.Handle("GET /api/lists/{id}", mw.RequireAuth(http.HandlerFunc(h.handleGetList)))
 	mux.Handle("PUT /api/lists/{id}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateList)))
-	mux.Handle("DELETE /api/lists/{id}", mw.RequireAuth(http.HandlerFunc(h.handleDeleteList)))
+	mux.Handle("PUT /api/lists/{id}/move", mw.RequireAuth(http.HandlerFunc(h.handleMoveList)))
+	mux.Handle("DELETE /api/lists/{id}", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDeleteList))))
+	mux.Handle("POST /api/lists/{id}/archive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleArchiveList))))
+	mux.Handle("POST /api/lists/{id}/unarchive", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleUnarchiveList))))
 }
 
 type CreateListRequest struct {
-	Name     string `json:"name"`
-	Position int32  `json:"position"`
+	Name     string `json:"name" validate:"required,max=255"`
+	AfterID  int32  `json:"after_id" validate:"min=0"`
+	BeforeID int32  `json:"before_id" validate:"min=0"`
 }
 
 type UpdateListRequest struct {
-	Name     string `json:"name"`
-	Position int32  `json:"position"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// MoveListRequest places the list on the board BoardID, right after the list AfterID and
+// before the list BeforeID. Without a board the list stays on its board; without anchors it goes last.
+type MoveListRequest struct {
+	BoardID  int32 `json:"board_id" validate:"min=0"`
+	AfterID  int32 `json:"after_id" validate:"min=0"`
+	BeforeID int32 `json:"before_id" validate:"min=0"`
 }
 
 // handleCreateList creates a new list in a board
 func (h *ListHandler) handleCreateList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse board ID from URL
 	boardIdStr := r.PathValue("boardId")
 	boardId, err := strconv.ParseInt(boardIdStr, 10, 32)
@@ -52,15 +71,14 @@ func (h *ListHandler) handleCreateList(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req CreateListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Create list
-	list, err := h.service.CreateList(r.Context(), req.Name, int32(boardId), req.Position)
+	list, err := h.service.CreateList(r.Context(), user.ID, req.Name, int32(boardId), req.AfterID, req.BeforeID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -69,6 +87,13 @@ func (h *ListHandler) handleCreateList(w http.ResponseWriter, r *http.Request) {
 
 // handleGetBoardLists gets all lists for a board
 func (h *ListHandler) handleGetBoardLists(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse board ID from URL
 	boardIdStr := r.PathValue("boardId")
 	boardId, err := strconv.ParseInt(boardIdStr, 10, 32)
@@ -77,10 +102,15 @@ func (h *ListHandler) handleGetBoardLists(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	archived, ok := archivedParam(w, r)
+	if !ok {
+		return
+	}
+
 	// Get board's lists
-	lists, err := h.service.GetBoardLists(r.Context(), int32(boardId))
+	lists, err := h.service.GetBoardLists(r.Context(), user.ID, int32(boardId), archived)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -89,6 +119,13 @@ func (h *ListHandler) handleGetBoardLists(w http.ResponseWriter, r *http.Request
 
 // handleGetList gets a single list by ID
 func (h *ListHandler) handleGetList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse list ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -98,9 +135,9 @@ func (h *ListHandler) handleGetList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get list
-	list, err := h.service.GetListByID(r.Context(), int32(id))
+	list, err := h.service.GetListByID(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "List not found")
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -109,6 +146,13 @@ func (h *ListHandler) handleGetList(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateList updates a list
 func (h *ListHandler) handleUpdateList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse list ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -119,23 +163,98 @@ func (h *ListHandler) handleUpdateList(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req UpdateListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Update list
-	list, err := h.service.UpdateList(r.Context(), int32(id), req.Name, req.Position)
+	list, err := h.service.UpdateList(r.Context(), user.ID, int32(id), req.Name)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, list)
+}
+
+// handleMoveList moves a list within its board or to another board
+func (h *ListHandler) handleMoveList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse list ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	// Parse request body
+	var req MoveListRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Move list
+	list, err := h.service.MoveList(r.Context(), user.ID, int32(id), req.BoardID, req.AfterID, req.BeforeID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, list)
+}
+
+// handleArchiveList archives a list
+func (h *ListHandler) handleArchiveList(w http.ResponseWriter, r *http.Request) {
+	h.setListArchived(w, r, true)
+}
+
+// handleUnarchiveList restores an archived list
+func (h *ListHandler) handleUnarchiveList(w http.ResponseWriter, r *http.Request) {
+	h.setListArchived(w, r, false)
+}
+
+// setListArchived archives or restores the list in the URL
+func (h *ListHandler) setListArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse list ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	list, err := h.service.SetArchived(r.Context(), user.ID, int32(id), archived)
+	if err != nil {
+		WriteServiceError(w, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, list)
 }
 
-// handleDeleteList deletes a list
+// handleDeleteList moves an archived list to the trash
 func (h *ListHandler) handleDeleteList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
 	// Parse list ID from URL
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
@@ -145,9 +264,9 @@ func (h *ListHandler) handleDeleteList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete list
-	err = h.service.DeleteList(r.Context(), int32(id))
+	err = h.service.DeleteList(r.Context(), user.ID, int32(id))
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
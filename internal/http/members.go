@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+)
+
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,max=255"`
+	Role  string `json:"role" validate:"max=20"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,max=20"`
+}
+
+// handleGetMembers gets all members of a board
+func (h *BoardHandler) handleGetMembers(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Get board members
+	members, err := h.service.GetMembers(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, members)
+}
+
+// handleAddMember adds an existing user to a board
+func (h *BoardHandler) handleAddMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	// Parse request body
+	var req AddMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Add member
+	member, err := h.service.AddMember(r.Context(), user.ID, int32(id), req.Email, req.Role)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, member)
+}
+
+// handleUpdateMember changes the role of a board member
+func (h *BoardHandler) handleUpdateMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board and member IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+	memberID, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Parse request body
+	var req UpdateMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Update member role
+	member, err := h.service.UpdateMemberRole(r.Context(), user.ID, int32(id), int32(memberID), req.Role)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, member)
+}
+
+// handleRemoveMember removes a member from a board
+func (h *BoardHandler) handleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board and member IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+	memberID, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Remove member
+	err = h.service.RemoveMember(r.Context(), user.ID, int32(id), int32(memberID))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Member removed successfully"})
+}
@@ -2,35 +2,112 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/session"
 )
 
 // contextKey is a custom type to avoid key collision in context.
 type contextKey string
 
-const userContextKey = contextKey("user")
+const (
+	userContextKey  = contextKey("user")
+	tokenContextKey = contextKey("accessToken")
+)
+
+// UnverifiedPolicy controls what users who have not verified their email may do.
+type UnverifiedPolicy string
+
+const (
+	// UnverifiedAllow places no restrictions on unverified users.
+	UnverifiedAllow UnverifiedPolicy = "allow"
+	// UnverifiedNoCreate stops unverified users from using routes wrapped in RequireVerified, like creating boards.
+	UnverifiedNoCreate UnverifiedPolicy = "no-create"
+	// UnverifiedReadOnly additionally limits unverified users to read-only requests.
+	UnverifiedReadOnly UnverifiedPolicy = "read-only"
+)
+
+// ParseUnverifiedPolicy converts a string into an UnverifiedPolicy, rejecting unknown policies.
+func ParseUnverifiedPolicy(s string) (UnverifiedPolicy, error) {
+	policy := UnverifiedPolicy(s)
+	switch policy {
+	case UnverifiedAllow, UnverifiedNoCreate, UnverifiedReadOnly:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid unverified email policy %q", s)
+}
 
 // Middleware struct holds dependencies for middleware
 type Middleware struct {
-	sm      *scs.SessionManager
-	queries *db.Queries
+	sm         *scs.SessionManager
+	queries    *db.Queries
+	sessions   *session.Service
+	tokens     *accesstoken.Service
+	unverified UnverifiedPolicy
 }
 
 // NewMiddleware creates a new Middleware struct.
-func NewMiddleware(sm *scs.SessionManager, queries *db.Queries) *Middleware {
+func NewMiddleware(sm *scs.SessionManager, queries *db.Queries, sessions *session.Service, tokens *accesstoken.Service, unverified UnverifiedPolicy) *Middleware {
 	return &Middleware{
-		sm:      sm,
-		queries: queries,
+		sm:         sm,
+		queries:    queries,
+		sessions:   sessions,
+		tokens:     tokens,
+		unverified: unverified,
 	}
 }
 
-// RequireAuth is the middleware that protects routes
+// RequireAuth is the middleware that protects routes.
+// Requests are authenticated by the session cookie or by an "Authorization: Bearer"
+// personal access token. Tokens need the read scope for safe requests and the write
+// scope for everything else. Under the read-only policy, unverified users can only
+// make safe requests.
 func (m *Middleware) RequireAuth(next http.Handler) http.Handler {
+	return m.requireAuth(next, true)
+}
+
+// RequireAuthUnverified protects routes that unverified users must always reach,
+// such as asking for a new verification email.
+func (m *Middleware) RequireAuthUnverified(next http.Handler) http.Handler {
+	return m.requireAuth(next, false)
+}
+
+// RequireVerified blocks unverified users unless the policy allows them everything.
+// It must be used inside RequireAuth.
+func (m *Middleware) RequireVerified(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if user is authenticated
+		user, ok := r.Context().Value(userContextKey).(db.User)
+		if !ok {
+			WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+			return
+		}
+
+		if m.unverified != UnverifiedAllow && !user.EmailVerifiedAt.Valid {
+			WriteError(w, http.StatusForbidden, "Please verify your email address first")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAuth loads the user from the session or an access token and optionally applies the unverified email policy
+func (m *Middleware) requireAuth(next http.Handler, enforceVerification bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Personal access tokens take precedence over the session cookie
+		if header := r.Header.Get("Authorization"); header != "" {
+			m.requireToken(w, r, next, header, enforceVerification)
+			return
+		}
+
+		// Check if user is authenticated. Logins waiting for a two-factor code
+		// only hold pendingTwoFactorUserID, so they are rejected here.
 		userID := m.sm.GetInt32(r.Context(), "authenticatedUserID")
 		if userID == 0 {
 			WriteError(w, http.StatusUnauthorized, "You must be logged in to access this resourse")
@@ -45,6 +122,17 @@ func (m *Middleware) RequireAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		// Unverified users may only read under the read-only policy
+		if enforceVerification && !m.allowUnverified(user, r) {
+			WriteError(w, http.StatusForbidden, "Please verify your email address first")
+			return
+		}
+
+		// Keep the session index up to date. A failure here should not block the request.
+		if err := m.sessions.Track(r.Context(), user.ID, m.sm.Token(r.Context()), clientIP(r), r.UserAgent()); err != nil {
+			log.Printf("Failed to track session for user %d: %v", user.ID, err)
+		}
+
 		// Add the user to request context
 		ctx := context.WithValue(r.Context(), userContextKey, user)
 
@@ -52,3 +140,69 @@ func (m *Middleware) RequireAuth(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// requireToken authenticates the request with a personal access token
+func (m *Middleware) requireToken(w http.ResponseWriter, r *http.Request, next http.Handler, header string, enforceVerification bool) {
+	plain, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, "Authorization header must use the Bearer scheme")
+		return
+	}
+
+	pat, err := m.tokens.Authenticate(r.Context(), strings.TrimSpace(plain))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	// Safe requests need the read scope, anything else needs write
+	required := accesstoken.ScopeWrite
+	if isSafeMethod(r.Method) {
+		required = accesstoken.ScopeRead
+	}
+	if !accesstoken.Allows(pat.Scopes, required) {
+		WriteError(w, http.StatusForbidden, fmt.Sprintf("This token needs the %s scope", required))
+		return
+	}
+
+	user, err := m.queries.GetUserByID(r.Context(), pat.UserID)
+	if err != nil {
+		WriteError(w, http.StatusUnauthorized, "Invalid authentication token")
+		return
+	}
+
+	if enforceVerification && !m.allowUnverified(user, r) {
+		WriteError(w, http.StatusForbidden, "Please verify your email address first")
+		return
+	}
+
+	// Add the user and the token to request context
+	ctx := context.WithValue(r.Context(), userContextKey, user)
+	ctx = context.WithValue(ctx, tokenContextKey, *pat)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireScope blocks personal access tokens that lack the scope.
+// Session-authenticated requests are not limited. It must be used inside RequireAuth.
+func (m *Middleware) RequireScope(scope accesstoken.Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pat, ok := r.Context().Value(tokenContextKey).(db.PersonalAccessToken)
+		if ok && !accesstoken.Allows(pat.Scopes, scope) {
+			WriteError(w, http.StatusForbidden, fmt.Sprintf("This token needs the %s scope", scope))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowUnverified applies the read-only policy to users who have not verified their email
+func (m *Middleware) allowUnverified(user db.User, r *http.Request) bool {
+	return m.unverified != UnverifiedReadOnly || user.EmailVerifiedAt.Valid || isSafeMethod(r.Method)
+}
+
+// isSafeMethod reports whether the HTTP method only reads data
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/notification"
+)
+
+// NotificationHandler handles HTTP requests for the current user's notifications
+type NotificationHandler struct {
+	service *notification.Service
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(service *notification.Service) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes adds the notification routes to router
+func (h *NotificationHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	mux.Handle("GET /api/me/notifications", mw.RequireAuth(http.HandlerFunc(h.handleGetNotifications)))
+	mux.Handle("POST /api/me/notifications/{id}/read", mw.RequireAuth(http.HandlerFunc(h.handleMarkRead)))
+}
+
+// handleGetNotifications gets the authenticated user's recent notifications.
+// With ?unread=true only unread ones are returned.
+func (h *NotificationHandler) handleGetNotifications(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	unreadOnly := false
+	if v := r.URL.Query().Get("unread"); v != "" {
+		var err error
+		unreadOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+	}
+
+	notifications, err := h.service.GetNotifications(r.Context(), user.ID, unreadOnly)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, notifications)
+}
+
+// handleMarkRead marks a notification as read
+func (h *NotificationHandler) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse notification ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	notification, err := h.service.MarkRead(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, notification)
+}
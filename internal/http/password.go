@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+)
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,max=255"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=512"`
+	Password string `json:"password" validate:"required"`
+}
+
+// handleForgotPassword sends a password reset link.
+// It always answers the same way so it cannot be used to find registered emails.
+// Requests are limited per email and per client address so nobody can be flooded with emails.
+func (h *UserHandler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.resets.Allow(r.Context(), loginAttempt(r, req.Email)); err != nil {
+		writeGuardError(w, err)
+		return
+	}
+
+	// Send the email in the background so the response time does not reveal whether the account exists
+	ctx := context.WithoutCancel(r.Context())
+	h.mail.Submit("password reset email", func() {
+		if err := h.service.RequestPasswordReset(ctx, req.Email); err != nil {
+			log.Printf("Failed to send password reset email: %v", err)
+		}
+	})
+
+	WriteJSON(w, http.StatusAccepted, map[string]string{"message": "If an account exists for that email, a password reset link has been sent"})
+}
+
+// handleResetPassword sets a new password using a reset token
+func (h *UserHandler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.service.ResetPassword(r.Context(), req.Token, req.Password)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Password has been reset. Please log in with your new password."})
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/anubhav047/goboard/internal/db"
+)
+
+// UpdateProfileRequest holds the fields to change; omitted fields are left as they are.
+// CurrentPassword is only needed to change the email.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" validate:"max=255"`
+	Email           *string `json:"email" validate:"max=255"`
+	CurrentPassword string  `json:"current_password" validate:"max=1024"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"max=1024"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// handleUpdateProfile changes the authenticated user's name and email.
+// Unverified users may use it to fix a mistyped email.
+func (h *UserHandler) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	var req UpdateProfileRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	updated, err := h.service.UpdateProfile(r.Context(), u.ID, req.Name, req.Email, req.CurrentPassword)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id":                updated.ID,
+		"name":              updated.Name,
+		"email":             updated.Email,
+		"email_verified_at": updated.EmailVerifiedAt,
+		"totp_enabled_at":   updated.TotpEnabledAt,
+		"created_at":        updated.CreatedAt,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// handleChangePassword replaces the authenticated user's password.
+// The current session stays logged in; all other sessions are revoked.
+func (h *UserHandler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.service.ChangePassword(r.Context(), u.ID, req.CurrentPassword, req.NewPassword, h.sm.Token(r.Context()))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Password changed successfully"})
+}
@@ -0,0 +1,33 @@
+package http
+
+import "log"
+
+// jobQueue runs jobs in the background on a fixed number of workers,
+// so a flood of requests cannot start an unbounded number of goroutines
+type jobQueue struct {
+	jobs chan func()
+}
+
+// newJobQueue starts the workers of a queue holding up to size waiting jobs
+func newJobQueue(workers, size int) *jobQueue {
+	q := &jobQueue{jobs: make(chan func(), size)}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range q.jobs {
+				job()
+			}
+		}()
+	}
+	return q
+}
+
+// Submit queues a job. When the queue is full the job is dropped and Submit returns false.
+func (q *jobQueue) Submit(name string, job func()) bool {
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		log.Printf("Background queue is full, dropping %s", name)
+		return false
+	}
+}
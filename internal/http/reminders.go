@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/card"
+)
+
+// AddReminderRequest sets how long before the due date the reminder fires, like "1h" or "24h"
+type AddReminderRequest struct {
+	Before string `json:"before" validate:"required,max=32"`
+}
+
+// handleGetReminders gets the reminders of a card
+func (h *CardHandler) handleGetReminders(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	// Get card reminders
+	reminders, err := h.service.GetReminders(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, reminders)
+}
+
+// handleAddReminder adds a due date reminder to a card
+func (h *CardHandler) handleAddReminder(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	// Parse request body
+	var req AddReminderRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	before, err := time.ParseDuration(req.Before)
+	if err != nil {
+		WriteServiceError(w, card.ErrInvalidReminderOffset)
+		return
+	}
+
+	// Add reminder
+	reminder, err := h.service.AddReminder(r.Context(), user.ID, int32(id), before)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, reminder)
+}
+
+// handleDeleteReminder removes a reminder from a card
+func (h *CardHandler) handleDeleteReminder(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card and reminder IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	reminderID, err := strconv.ParseInt(r.PathValue("reminderId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid reminder ID")
+		return
+	}
+
+	// Delete reminder
+	err = h.service.DeleteReminder(r.Context(), user.ID, int32(id), int32(reminderID))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Reminder deleted successfully"})
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/session"
+)
+
+// establishSession logs the user in: it renews the session token to prevent
+// session fixation, stores the user ID and records the session in the index.
+func establishSession(r *http.Request, sm *scs.SessionManager, sessions *session.Service, userID int32) error {
+	if err := sm.RenewToken(r.Context()); err != nil {
+		return err
+	}
+	sm.Put(r.Context(), "authenticatedUserID", userID)
+
+	// Record the new session so it shows up in the user's session list
+	if err := sessions.Track(r.Context(), userID, sm.Token(r.Context()), clientIP(r), r.UserAgent()); err != nil {
+		log.Printf("Failed to track session for user %d: %v", userID, err)
+	}
+
+	return nil
+}
+
+// handleLogout ends the current session
+func (h *UserHandler) handleLogout(w http.ResponseWriter, r *http.Request) {
+	// Remove the session from the index before the token is discarded
+	if token := h.sm.Token(r.Context()); token != "" {
+		if err := h.sessions.Forget(r.Context(), token); err != nil {
+			log.Printf("Failed to forget session: %v", err)
+		}
+	}
+
+	if err := h.sm.Destroy(r.Context()); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to destroy session")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
+}
+
+// handleGetSessions lists the authenticated user's active sessions
+func (h *UserHandler) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	sessions, err := h.sessions.List(r.Context(), user.ID, h.sm.Token(r.Context()))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, sessions)
+}
+
+// handleRevokeSession ends one of the authenticated user's sessions
+func (h *UserHandler) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse session ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	err = h.sessions.Revoke(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Session revoked successfully"})
+}
+
+// handleRevokeOtherSessions ends every session of the authenticated user except the current one
+func (h *UserHandler) handleRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	err := h.sessions.RevokeOthers(r.Context(), user.ID, h.sm.Token(r.Context()))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Other sessions revoked successfully"})
+}
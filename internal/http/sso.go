@@ -0,0 +1,135 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/session"
+	"github.com/anubhav047/goboard/internal/services/sso"
+)
+
+// SSOHandler handles single sign-on through OpenID Connect providers
+type SSOHandler struct {
+	service      *sso.Service
+	sessions     *session.Service
+	sm           *scs.SessionManager
+	appURL       string
+	twoFactorURL string
+}
+
+// NewSSOHandler creates a new SSOHandler. Successful logins are redirected to appURL.
+func NewSSOHandler(service *sso.Service, sessions *session.Service, sm *scs.SessionManager, appURL string) *SSOHandler {
+	return &SSOHandler{
+		service:      service,
+		sessions:     sessions,
+		sm:           sm,
+		appURL:       appURL,
+		twoFactorURL: withQuery(appURL, "two_factor_required", "true"),
+	}
+}
+
+// withQuery adds a query parameter to a URL, keeping any it already has
+func withQuery(rawURL, key, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// RegisterRoutes adds the SSO routes to router
+func (h *SSOHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	mux.HandleFunc("GET /api/auth/oidc/providers", h.handleGetProviders)
+	mux.HandleFunc("GET /api/auth/oidc/{provider}/login", h.handleStartLogin)
+	mux.HandleFunc("GET /api/auth/oidc/{provider}/callback", h.handleCallback)
+}
+
+// handleGetProviders lists the configured identity providers
+func (h *SSOHandler) handleGetProviders(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, h.service.Providers())
+}
+
+// handleStartLogin redirects the browser to the identity provider.
+// The state, PKCE verifier and nonce are kept in the session for the callback.
+func (h *SSOHandler) handleStartLogin(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	url, login, err := h.service.Start(provider)
+	if err != nil {
+		writeSSOError(w, err)
+		return
+	}
+
+	h.sm.Put(r.Context(), "oidcProvider", provider)
+	h.sm.Put(r.Context(), "oidcState", login.State)
+	h.sm.Put(r.Context(), "oidcVerifier", login.Verifier)
+	h.sm.Put(r.Context(), "oidcNonce", login.Nonce)
+
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// handleCallback completes the login and creates the same session as a password login.
+// Users with two-factor authentication get a pending login instead and are sent to the
+// app with two_factor_required=true, so the code is checked no matter how they signed in.
+func (h *SSOHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	// Each login attempt can only be completed once
+	login := sso.Login{
+		State:    h.sm.PopString(r.Context(), "oidcState"),
+		Verifier: h.sm.PopString(r.Context(), "oidcVerifier"),
+		Nonce:    h.sm.PopString(r.Context(), "oidcNonce"),
+	}
+	if h.sm.PopString(r.Context(), "oidcProvider") != provider {
+		writeSSOError(w, sso.ErrInvalidState)
+		return
+	}
+
+	query := r.URL.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		log.Printf("Identity provider %s returned an error: %s %s", provider, errCode, query.Get("error_description"))
+		WriteError(w, http.StatusUnauthorized, "Sign-in was cancelled or denied by the identity provider")
+		return
+	}
+
+	user, err := h.service.Finish(r.Context(), provider, login, query.Get("state"), query.Get("code"))
+	if err != nil {
+		writeSSOError(w, err)
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		if err := beginTwoFactor(r, h.sm, user); err != nil {
+			WriteError(w, http.StatusInternalServerError, "Failed to renew session token")
+			return
+		}
+		log.Printf("SSO login for user %d is waiting for a two-factor code.", user.ID)
+		http.Redirect(w, r, h.twoFactorURL, http.StatusFound)
+		return
+	}
+
+	if err := establishSession(r, h.sm, h.sessions, user.ID); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to renew session token")
+		return
+	}
+
+	log.Printf("SSO login successful. User ID %d put into session.", user.ID)
+	http.Redirect(w, r, h.appURL, http.StatusFound)
+}
+
+// writeSSOError maps SSO errors to their HTTP status codes.
+// Unexpected failures usually come from the identity provider, so they answer 401.
+func writeSSOError(w http.ResponseWriter, err error) {
+	if errs.KindOf(err) == errs.Internal {
+		log.Printf("SSO login failed: %v", err)
+		WriteError(w, http.StatusUnauthorized, "Sign-in with the identity provider failed")
+		return
+	}
+	WriteServiceError(w, err)
+}
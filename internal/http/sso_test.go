@@ -0,0 +1,215 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/loginguard"
+	"github.com/anubhav047/goboard/internal/services/session"
+	"github.com/anubhav047/goboard/internal/services/sso"
+	"github.com/anubhav047/goboard/internal/services/sso/ssotest"
+	"github.com/anubhav047/goboard/internal/services/user"
+	"github.com/anubhav047/goboard/internal/totp"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// appURL is where successful logins are sent
+const appURL = "http://app.goboard.test/"
+
+// newSSOServer serves the SSO and user routes with a mock issuer configured as "mock".
+// The returned client keeps cookies and stops at the first redirect to the app.
+func newSSOServer(t *testing.T, queries *db.Queries) (*httptest.Server, *http.Client, *ssotest.Issuer) {
+	t.Helper()
+
+	issuer, err := ssotest.NewIssuer("goboard", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(issuer.Close)
+
+	sm := scs.New()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(sm.LoadAndSave(mux))
+	t.Cleanup(server.Close)
+
+	service, err := sso.New(context.Background(), queries, []sso.ProviderConfig{{
+		Name:         "mock",
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  server.URL + "/api/auth/oidc/mock/callback",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.New(queries)
+	mw := NewMiddleware(sm, queries, sessions, accesstoken.New(queries), UnverifiedAllow)
+	NewSSOHandler(service, sessions, sm, appURL).RegisterRoutes(mux, mw)
+	store := loginguard.NewMemoryStore()
+	guard := loginguard.New(store, queries, loginguard.DefaultConfig())
+	resets := loginguard.NewLimiter(store, "reset", loginguard.LimiterConfig{PerEmail: 3, PerIP: 20, Window: time.Hour})
+	NewUserHandler(user.New(queries, mail.LogMailer{}, user.Config{}), sessions, guard, resets, sm).RegisterRoutes(mux, mw)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if strings.HasPrefix(req.URL.String(), appURL) {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	return server, client, issuer
+}
+
+func TestSSOCallbackSignsIn(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	server, client, issuer := newSSOServer(t, queries)
+	issuer.SignInAs(ssotest.Identity{Subject: "alice-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	// The login redirects to the issuer, which redirects back to the callback
+	resp, err := client.Get(server.URL + "/api/auth/oidc/mock/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != appURL {
+		t.Fatalf("callback answered %d to %q, want a redirect to %s", resp.StatusCode, resp.Header.Get("Location"), appURL)
+	}
+	if resp.Request.URL.Path != "/api/auth/oidc/mock/callback" {
+		t.Errorf("last request was %s, want the callback", resp.Request.URL.Path)
+	}
+
+	if _, err := queries.GetUserByIdentity(context.Background(), db.GetUserByIdentityParams{Provider: "mock", Subject: "alice-1"}); err != nil {
+		t.Fatalf("identity was not linked: %v", err)
+	}
+}
+
+func TestSSOCallbackRequiresTwoFactor(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	server, client, issuer := newSSOServer(t, queries)
+	ctx := context.Background()
+
+	// A verified local account with two-factor authentication, which the login links to
+	local, err := queries.CreateUser(ctx, db.CreateUserParams{Name: "Carol", Email: "carol@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{ID: local.ID, Email: local.Email}); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queries.SetPendingTOTPSecret(ctx, db.SetPendingTOTPSecretParams{ID: local.ID, TotpSecret: pgtype.Text{String: secret, Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+	step := totp.Step(time.Now())
+	if _, err := queries.EnableTOTP(ctx, db.EnableTOTPParams{ID: local.ID, TotpLastStep: pgtype.Int8{Int64: step - 10, Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.SignInAs(ssotest.Identity{Subject: "carol-1", Email: "carol@example.com", EmailVerified: true})
+	resp, err := client.Get(server.URL + "/api/auth/oidc/mock/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := appURL + "?two_factor_required=true"; resp.Header.Get("Location") != want {
+		t.Fatalf("callback redirected to %q, want %q", resp.Header.Get("Location"), want)
+	}
+
+	// The pending login is not signed in until the code is entered
+	if status := getStatus(t, client, server.URL+"/api/me"); status != http.StatusUnauthorized {
+		t.Fatalf("GET /api/me before the code answered %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	code, err := totp.Code(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.Post(server.URL+"/api/login/2fa", "application/json", strings.NewReader(`{"code":"`+code+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /api/login/2fa answered %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if status := getStatus(t, client, server.URL+"/api/me"); status != http.StatusOK {
+		t.Errorf("GET /api/me after the code answered %d, want %d", status, http.StatusOK)
+	}
+}
+
+// getStatus returns the status of a GET request
+func getStatus(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestSSOCallbackRejectsForgedState(t *testing.T) {
+	server, client, issuer := newSSOServer(t, nil)
+	issuer.SignInAs(ssotest.Identity{Subject: "bob-1", Email: "bob@example.com", EmailVerified: true})
+
+	// Start a login but answer the callback with a state of our own
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Get(server.URL + "/api/auth/oidc/mock/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	_, code, err := issuer.Authorize(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callback := server.URL + "/api/auth/oidc/mock/callback?" + url.Values{"state": {"forged"}, "code": {code}}.Encode()
+	resp, err = client.Get(callback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("forged state answered %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestSSOCallbackWithoutLogin(t *testing.T) {
+	server, client, _ := newSSOServer(t, nil)
+
+	resp, err := client.Get(server.URL + "/api/auth/oidc/mock/callback?state=x&code=y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("callback without a login answered %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
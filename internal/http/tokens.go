@@ -0,0 +1,115 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+)
+
+// TokenHandler handles HTTP requests for personal access tokens
+type TokenHandler struct {
+	service *accesstoken.Service
+}
+
+// NewTokenHandler creates a new TokenHandler
+func NewTokenHandler(service *accesstoken.Service) *TokenHandler {
+	return &TokenHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes adds the token routes to router
+func (h *TokenHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	// Managing tokens with a token requires the admin scope
+	mux.Handle("GET /api/me/tokens", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleGetTokens))))
+	mux.Handle("POST /api/me/tokens", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleCreateToken))))
+	mux.Handle("DELETE /api/me/tokens/{id}", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRevokeToken))))
+}
+
+type CreateTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=255"`
+	Scopes        []string `json:"scopes" validate:"required,max=3,oneof=read write admin"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"min=0,max=365"`
+}
+
+// handleCreateToken issues a new personal access token.
+// The token itself is only ever shown in this response.
+func (h *TokenHandler) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse request body
+	var req CreateTokenRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	plain, pat, err := h.service.Create(r.Context(), user.ID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id":           pat.ID,
+		"name":         pat.Name,
+		"token":        plain,
+		"scopes":       pat.Scopes,
+		"expires_at":   pat.ExpiresAt,
+		"last_used_at": pat.LastUsedAt,
+		"created_at":   pat.CreatedAt,
+	}
+	WriteJSON(w, http.StatusCreated, response)
+}
+
+// handleGetTokens lists the authenticated user's personal access tokens
+func (h *TokenHandler) handleGetTokens(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	tokens, err := h.service.List(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, tokens)
+}
+
+// handleRevokeToken deletes one of the authenticated user's personal access tokens
+func (h *TokenHandler) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse token ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	err = h.service.Revoke(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Token revoked successfully"})
+}
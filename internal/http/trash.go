@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/trash"
+)
+
+// TrashHandler handles HTTP requests for deleted boards, lists and cards
+type TrashHandler struct {
+	service *trash.Service
+}
+
+// NewTrashHandler creates a new TrashHandler
+func NewTrashHandler(service *trash.Service) *TrashHandler {
+	return &TrashHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes adds the trash routes to router
+func (h *TrashHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	mux.Handle("GET /api/trash", mw.RequireAuth(http.HandlerFunc(h.handleGetTrash)))
+	// Restoring needs the same scope as deleting
+	mux.Handle("POST /api/trash/boards/{id}/restore", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRestoreBoard))))
+	mux.Handle("POST /api/trash/lists/{id}/restore", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRestoreList))))
+	mux.Handle("POST /api/trash/cards/{id}/restore", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRestoreCard))))
+}
+
+// handleGetTrash gets the items the authenticated user has deleted
+func (h *TrashHandler) handleGetTrash(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	items, err := h.service.GetTrash(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, items)
+}
+
+// handleRestoreBoard restores a deleted board with its lists and cards
+func (h *TrashHandler) handleRestoreBoard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse board ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid board ID")
+		return
+	}
+
+	board, err := h.service.RestoreBoard(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, board)
+}
+
+// handleRestoreList restores a deleted list with its cards
+func (h *TrashHandler) handleRestoreList(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse list ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	list, err := h.service.RestoreList(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, list)
+}
+
+// handleRestoreCard restores a deleted card
+func (h *TrashHandler) handleRestoreCard(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse card ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.service.RestoreCard(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, card)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/user"
+)
+
+const (
+	// twoFactorTimeout is how long a pending login waits for its code
+	twoFactorTimeout = 5 * time.Minute
+	// twoFactorMaxAttempts is how many wrong codes a pending login allows
+	twoFactorMaxAttempts = 5
+)
+
+type TwoFactorRequest struct {
+	Code string `json:"code" validate:"required,max=64"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" validate:"max=1024"`
+	Code     string `json:"code" validate:"required,max=64"`
+}
+
+// startTwoFactor stores a pending login in the session after a correct password
+func (h *UserHandler) startTwoFactor(w http.ResponseWriter, r *http.Request, userr db.User) {
+	if err := beginTwoFactor(r, h.sm, userr); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to renew session token")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"two_factor_required": true})
+}
+
+// beginTwoFactor puts a pending login into a fresh session, for password and SSO logins alike.
+// The pending session has no authenticatedUserID, so RequireAuth rejects it
+// until the code is verified through POST /api/login/2fa.
+func beginTwoFactor(r *http.Request, sm *scs.SessionManager, userr db.User) error {
+	if err := sm.RenewToken(r.Context()); err != nil {
+		return err
+	}
+	sm.Remove(r.Context(), "authenticatedUserID")
+	sm.Put(r.Context(), "pendingTwoFactorUserID", userr.ID)
+	sm.Put(r.Context(), "pendingTwoFactorEmail", userr.Email)
+	sm.Put(r.Context(), "pendingTwoFactorStartedAt", time.Now().Unix())
+	sm.Put(r.Context(), "pendingTwoFactorAttempts", 0)
+
+	return nil
+}
+
+// handleLoginTwoFactor finishes a pending login with a TOTP or recovery code
+func (h *UserHandler) handleLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := h.sm.GetInt32(r.Context(), "pendingTwoFactorUserID")
+	startedAt := time.Unix(h.sm.GetInt64(r.Context(), "pendingTwoFactorStartedAt"), 0)
+	if userID == 0 || time.Since(startedAt) > twoFactorTimeout {
+		h.clearTwoFactor(r)
+		WriteError(w, http.StatusUnauthorized, "No login is waiting for a two-factor code")
+		return
+	}
+
+	var req TwoFactorRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Wrong codes count towards the same limits as wrong passwords
+	reservation, err := h.guard.Reserve(r.Context(), loginAttempt(r, h.sm.GetString(r.Context(), "pendingTwoFactorEmail")))
+	if err != nil {
+		writeGuardError(w, err)
+		return
+	}
+
+	userr, err := h.service.VerifyTOTP(r.Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidTOTPCode) {
+			h.guard.Fail(r.Context(), reservation, userID)
+
+			// Too many wrong codes end the pending login, so the password must be entered again
+			attempts := h.sm.GetInt(r.Context(), "pendingTwoFactorAttempts") + 1
+			if attempts >= twoFactorMaxAttempts {
+				log.Printf("Too many two-factor attempts for user %d", userID)
+				h.clearTwoFactor(r)
+			} else {
+				h.sm.Put(r.Context(), "pendingTwoFactorAttempts", attempts)
+			}
+			WriteError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		h.releaseAttempt(r, reservation)
+		if errors.Is(err, user.ErrTOTPNotEnabled) || errors.Is(err, user.ErrInvalidCredentials) {
+			h.clearTwoFactor(r)
+			WriteError(w, http.StatusUnauthorized, "No login is waiting for a two-factor code")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "An unexpected error occurred")
+		return
+	}
+
+	if err := h.guard.Succeed(r.Context(), reservation, userr.ID); err != nil {
+		log.Printf("Failed to record successful login: %v", err)
+	}
+	h.clearTwoFactor(r)
+	h.completeLogin(w, r, userr)
+}
+
+// clearTwoFactor removes the pending login from the session
+func (h *UserHandler) clearTwoFactor(r *http.Request) {
+	h.sm.Remove(r.Context(), "pendingTwoFactorUserID")
+	h.sm.Remove(r.Context(), "pendingTwoFactorEmail")
+	h.sm.Remove(r.Context(), "pendingTwoFactorStartedAt")
+	h.sm.Remove(r.Context(), "pendingTwoFactorAttempts")
+}
+
+// handleEnrollTOTP starts 2FA enrollment and returns the secret and otpauth URI
+func (h *UserHandler) handleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	secret, uri, err := h.service.EnrollTOTP(r.Context(), u)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{
+		"secret":      secret,
+		"otpauth_uri": uri,
+	})
+}
+
+// handleConfirmTOTP enables 2FA and returns the recovery codes.
+// They are only ever shown in this response.
+func (h *UserHandler) handleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	var req TwoFactorRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	codes, err := h.service.ConfirmTOTP(r.Context(), u.ID, req.Code)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"recovery_codes": codes})
+}
+
+// handleDisableTOTP turns 2FA off after checking the password and a code
+func (h *UserHandler) handleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	var req DisableTOTPRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.service.DisableTOTP(r.Context(), u.ID, req.Password, req.Code)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Two-factor authentication disabled"})
+}
@@ -1,27 +1,37 @@
 package http
 
 import (
-	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/loginguard"
+	"github.com/anubhav047/goboard/internal/services/session"
 	"github.com/anubhav047/goboard/internal/services/user"
 )
 
 // UserHandler handles HTTP requests for users.
 type UserHandler struct {
-	service *user.Service
-	sm      *scs.SessionManager
+	service  *user.Service
+	sessions *session.Service
+	guard    *loginguard.Guard
+	resets   *loginguard.Limiter
+	mail     *jobQueue
+	sm       *scs.SessionManager
 }
 
-// NewUserHandler creates a new UserHandler.
-func NewUserHandler(service *user.Service, sm *scs.SessionManager) *UserHandler {
+// NewUserHandler creates a new UserHandler. resets limits password reset requests.
+func NewUserHandler(service *user.Service, sessions *session.Service, guard *loginguard.Guard, resets *loginguard.Limiter, sm *scs.SessionManager) *UserHandler {
 	return &UserHandler{
-		service: service,
-		sm:      sm,
+		service:  service,
+		sessions: sessions,
+		guard:    guard,
+		resets:   resets,
+		mail:     newJobQueue(2, 100),
+		sm:       sm,
 	}
 }
 
@@ -29,28 +39,40 @@ func NewUserHandler(service *user.Service, sm *scs.SessionManager) *UserHandler
 func (h *UserHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
 	mux.HandleFunc("POST /api/register", h.handleRegister)
 	mux.HandleFunc("POST /api/login", h.handleLogin)
+	mux.HandleFunc("POST /api/login/2fa", h.handleLoginTwoFactor)
+	mux.HandleFunc("POST /api/logout", h.handleLogout)
+	mux.HandleFunc("POST /api/password/forgot", h.handleForgotPassword)
+	mux.HandleFunc("POST /api/password/reset", h.handleResetPassword)
 	mux.Handle("GET /api/me", mw.RequireAuth(http.HandlerFunc(h.handleMe)))
+	mux.Handle("PATCH /api/me", mw.RequireAuthUnverified(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleUpdateProfile))))
+	mux.Handle("POST /api/me/password", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleChangePassword))))
+	mux.Handle("GET /api/me/sessions", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleGetSessions))))
+	mux.Handle("DELETE /api/me/sessions", mw.RequireAuthUnverified(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRevokeOtherSessions))))
+	mux.Handle("DELETE /api/me/sessions/{id}", mw.RequireAuthUnverified(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleRevokeSession))))
+	mux.Handle("POST /api/me/2fa/totp", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleEnrollTOTP))))
+	mux.Handle("POST /api/me/2fa/totp/confirm", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleConfirmTOTP))))
+	mux.Handle("DELETE /api/me/2fa/totp", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDisableTOTP))))
+	mux.HandleFunc("POST /api/verify-email", h.handleVerifyEmail)
+	mux.Handle("POST /api/verify-email/resend", mw.RequireAuthUnverified(http.HandlerFunc(h.handleResendVerification)))
 }
 
 type RegisterRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string `json:"name" validate:"required,max=255"`
+	Email    string `json:"email" validate:"required,max=255"`
+	Password string `json:"password" validate:"required"`
 }
 
 // handleRegister is the handler for the user registration endpoint.
 func (h *UserHandler) handleRegister(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Execute Business Logic and passing request context all the way to service
 	user, err := h.service.Register(r.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
-		// TODO : Add specific checks like duplicate email
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteServiceError(w, err)
 		return
 	}
 
@@ -65,15 +87,21 @@ func (h *UserHandler) handleRegister(w http.ResponseWriter, r *http.Request) {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,max=1024"`
 }
 
 func (h *UserHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	// Decode the request body.
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Refuse the attempt while the account or address is backing off
+	reservation, err := h.guard.Reserve(r.Context(), loginAttempt(r, req.Email))
+	if err != nil {
+		writeGuardError(w, err)
 		return
 	}
 
@@ -82,21 +110,42 @@ func (h *UserHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// Check if it's our specific invalid credentials error.
 		if errors.Is(err, user.ErrInvalidCredentials) {
+			h.guard.Fail(r.Context(), reservation, 0)
 			WriteError(w, http.StatusUnauthorized, err.Error())
 			return
 		}
+		h.releaseAttempt(r, reservation)
 		// For all other errors, return a generic server error.
 		WriteError(w, http.StatusInternalServerError, "An unexpected error occurred")
 		return
 	}
 
-	// Use the session manager from the handler struct.
-	err = h.sm.RenewToken(r.Context())
-	if err != nil {
+	// Users with two-factor authentication only get a pending session until they enter a code
+	if userr.TotpEnabledAt.Valid {
+		h.releaseAttempt(r, reservation)
+		h.startTwoFactor(w, r, userr)
+		return
+	}
+
+	if err := h.guard.Succeed(r.Context(), reservation, userr.ID); err != nil {
+		log.Printf("Failed to record successful login: %v", err)
+	}
+	h.completeLogin(w, r, userr)
+}
+
+// releaseAttempt gives back a login attempt that did not fail
+func (h *UserHandler) releaseAttempt(r *http.Request, reservation *loginguard.Reservation) {
+	if err := h.guard.Release(r.Context(), reservation); err != nil {
+		log.Printf("Failed to release login attempt: %v", err)
+	}
+}
+
+// completeLogin puts the user into a fresh session and records it
+func (h *UserHandler) completeLogin(w http.ResponseWriter, r *http.Request, userr db.User) {
+	if err := establishSession(r, h.sm, h.sessions, userr.ID); err != nil {
 		WriteError(w, http.StatusInternalServerError, "Failed to renew session token")
 		return
 	}
-	h.sm.Put(r.Context(), "authenticatedUserID", userr.ID)
 
 	log.Printf("Login successful. User ID %d put into session.", userr.ID)
 
@@ -118,10 +167,12 @@ func (h *UserHandler) handleMe(w http.ResponseWriter, r *http.Request) {
 
 	// Send the user's details back as a response
 	response := map[string]interface{}{
-		"id":         user.ID,
-		"name":       user.Name,
-		"email":      user.Email,
-		"created_at": user.CreatedAt,
+		"id":                user.ID,
+		"name":              user.Name,
+		"email":             user.Email,
+		"email_verified_at": user.EmailVerifiedAt,
+		"totp_enabled_at":   user.TotpEnabledAt,
+		"created_at":        user.CreatedAt,
 	}
 	WriteJSON(w, http.StatusOK, response)
 }
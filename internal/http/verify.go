@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/anubhav047/goboard/internal/db"
+)
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=512"`
+}
+
+// handleVerifyEmail confirms the email address using the emailed token.
+// It does not require a session so the link works in any browser.
+func (h *UserHandler) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	verified, err := h.service.VerifyEmail(r.Context(), req.Token)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id":                verified.ID,
+		"email":             verified.Email,
+		"email_verified_at": verified.EmailVerifiedAt,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// handleResendVerification emails a new verification link to the authenticated user
+func (h *UserHandler) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user from the context.
+	u, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	err := h.service.ResendVerification(r.Context(), u)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Verification email sent"})
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/workspace"
+)
+
+// WorkspaceHandler handles HTTP requests for workspaces
+type WorkspaceHandler struct {
+	service *workspace.Service
+}
+
+// NewWorkspaceHandler creates a new WorkspaceHandler
+func NewWorkspaceHandler(service *workspace.Service) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes adds the workspace routes to router
+func (h *WorkspaceHandler) RegisterRoutes(mux *http.ServeMux, mw *Middleware) {
+	// All workspace routes require authentication
+	mux.Handle("GET /api/workspaces", mw.RequireAuth(http.HandlerFunc(h.handleGetUserWorkspaces)))
+	mux.Handle("POST /api/workspaces", mw.RequireAuth(mw.RequireVerified(http.HandlerFunc(h.handleCreateWorkspace))))
+	mux.Handle("GET /api/workspaces/{id}", mw.RequireAuth(http.HandlerFunc(h.handleGetWorkspace)))
+	mux.Handle("PUT /api/workspaces/{id}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateWorkspace)))
+	mux.Handle("DELETE /api/workspaces/{id}", mw.RequireAuth(mw.RequireScope(accesstoken.ScopeAdmin, http.HandlerFunc(h.handleDeleteWorkspace))))
+	mux.Handle("GET /api/workspaces/{id}/boards", mw.RequireAuth(http.HandlerFunc(h.handleGetWorkspaceBoards)))
+
+	// Workspace membership routes
+	mux.Handle("GET /api/workspaces/{id}/members", mw.RequireAuth(http.HandlerFunc(h.handleGetMembers)))
+	mux.Handle("POST /api/workspaces/{id}/members", mw.RequireAuth(http.HandlerFunc(h.handleAddMember)))
+	mux.Handle("PUT /api/workspaces/{id}/members/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleUpdateMember)))
+	mux.Handle("DELETE /api/workspaces/{id}/members/{userId}", mw.RequireAuth(http.HandlerFunc(h.handleRemoveMember)))
+}
+
+type CreateWorkspaceRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+}
+
+// handleCreateWorkspace creates a new workspace
+func (h *WorkspaceHandler) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse request body
+	var req CreateWorkspaceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Create workspace
+	ws, err := h.service.CreateWorkspace(r.Context(), user.ID, req.Name, req.Description)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, ws)
+}
+
+// handleGetUserWorkspaces gets all workspaces the authenticated user belongs to
+func (h *WorkspaceHandler) handleGetUserWorkspaces(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	workspaces, err := h.service.GetUserWorkspaces(r.Context(), user.ID)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, workspaces)
+}
+
+// handleGetWorkspace gets a single workspace by ID
+func (h *WorkspaceHandler) handleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	ws, err := h.service.GetWorkspaceByID(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, ws)
+}
+
+// handleUpdateWorkspace updates a workspace
+func (h *WorkspaceHandler) handleUpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	// Parse request body
+	var req UpdateWorkspaceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	ws, err := h.service.UpdateWorkspace(r.Context(), user.ID, int32(id), req.Name, req.Description)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, ws)
+}
+
+// handleDeleteWorkspace deletes a workspace
+func (h *WorkspaceHandler) handleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	err = h.service.DeleteWorkspace(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Workspace deleted successfully"})
+}
+
+// handleGetWorkspaceBoards gets the workspace boards visible to the authenticated user
+func (h *WorkspaceHandler) handleGetWorkspaceBoards(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	boards, err := h.service.GetWorkspaceBoards(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, boards)
+}
+
+// handleGetMembers gets all members of a workspace
+func (h *WorkspaceHandler) handleGetMembers(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	members, err := h.service.GetMembers(r.Context(), user.ID, int32(id))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, members)
+}
+
+// handleAddMember adds an existing user to a workspace
+func (h *WorkspaceHandler) handleAddMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace ID from URL
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	// Parse request body
+	var req AddMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	member, err := h.service.AddMember(r.Context(), user.ID, int32(id), req.Email, req.Role)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, member)
+}
+
+// handleUpdateMember changes the role of a workspace member
+func (h *WorkspaceHandler) handleUpdateMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace and member IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+	memberID, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Parse request body
+	var req UpdateMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(r.Context(), user.ID, int32(id), int32(memberID), req.Role)
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, member)
+}
+
+// handleRemoveMember removes a member from a workspace
+func (h *WorkspaceHandler) handleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "Error retrieving user from context")
+		return
+	}
+
+	// Parse workspace and member IDs from URL
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+	memberID, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.service.RemoveMember(r.Context(), user.ID, int32(id), int32(memberID))
+	if err != nil {
+		WriteServiceError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Member removed successfully"})
+}
@@ -0,0 +1,120 @@
+// Package rank generates sort keys for ordered items like cards and lists.
+// Keys are base-62 strings compared byte by byte, so an item can always be moved
+// between two others by giving it a new key, without touching its neighbours.
+// Keys never end in "0", which guarantees there is room below every key.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits are ordered by byte value, so keys sort correctly with a "C" collation
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var (
+	// ErrInvalidKey is returned for keys with characters outside the alphabet or a trailing "0"
+	ErrInvalidKey = errors.New("rank: invalid key")
+	// ErrOutOfOrder is returned when the lower key does not sort before the upper key
+	ErrOutOfOrder = errors.New("rank: keys are out of order")
+)
+
+// Between returns a key that sorts strictly between a and b.
+// An empty a means the start of the sequence and an empty b its end.
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidKey
+	}
+	if b != "" && a >= b {
+		return "", ErrOutOfOrder
+	}
+
+	return midpoint(a, b), nil
+}
+
+// Spread returns n evenly spaced keys in ascending order.
+// They are as short as possible while leaving gaps for later inserts.
+func Spread(n int) []string {
+	length, capacity := 1, base
+	for capacity <= 2*n {
+		length++
+		capacity *= base
+	}
+
+	step := capacity / (n + 1)
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strings.TrimRight(encode((i+1)*step, length), "0")
+	}
+
+	return keys
+}
+
+// midpoint finds a key between a and b, which are valid and in order
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, treating missing digits of a as zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	lo, hi := 0, base
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// The first digits are adjacent. A longer b leaves room right after its first digit,
+	// otherwise keep a's first digit and look for a key after the rest of a.
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[lo]) + midpoint(rest, "")
+}
+
+// digitAt returns the digit of key at i, or "0" past its end
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return digits[0]
+}
+
+// encode writes v as a base-62 number padded to length digits
+func encode(v, length int) string {
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = digits[v%base]
+		v /= base
+	}
+	return string(b)
+}
+
+func valid(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(key, "0")
+}
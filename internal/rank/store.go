@@ -0,0 +1,143 @@
+package rank
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/anubhav047/goboard/internal/errs"
+)
+
+var (
+	// ErrInvalidAnchor is returned when an anchor is missing, in another group, the item
+	// being placed, or when the anchors are out of order
+	ErrInvalidAnchor = errors.New("rank: invalid anchor")
+	// ErrGroupNotFound is returned when locking a group that does not exist
+	ErrGroupNotFound = errors.New("rank: group not found")
+)
+
+// Store gives access to one kind of ranked item, like cards ordered within their list.
+// What the items are ordered within is their group. Each kind of item has a small
+// adapter over its queries; methods run in the caller's transaction.
+// Next, Prev and Last return an error matching errs.IsNoRows when there is no such item.
+type Store interface {
+	// Item returns the group and rank of an item
+	Item(ctx context.Context, id int32) (group int32, key string, err error)
+	// Next returns the lowest rank in the group above key, skipping the item exclude
+	Next(ctx context.Context, group int32, key string, exclude int32) (string, error)
+	// Prev returns the highest rank in the group below key, skipping the item exclude
+	Prev(ctx context.Context, group int32, key string, exclude int32) (string, error)
+	// Last returns the highest rank in the group, skipping the item exclude
+	Last(ctx context.Context, group, exclude int32) (string, error)
+	// Lock locks the groups in ID order and returns the IDs of those that exist
+	Lock(ctx context.Context, groups []int32) ([]int32, error)
+	// Ranked returns the IDs of the items in a group in rank order
+	Ranked(ctx context.Context, group int32) ([]int32, error)
+	// SetRanks gives each item the key at the same position
+	SetRanks(ctx context.Context, ids []int32, keys []string) error
+}
+
+// Place returns a key that puts an item in group right after the item afterID and
+// before the item beforeID. Either anchor may be 0; with neither the item goes last.
+// id is the item being placed, or 0 for a new item.
+// The group should be locked so concurrent placements cannot pick the same key.
+func Place(ctx context.Context, s Store, group, id, afterID, beforeID int32) (string, error) {
+	lo, err := anchor(ctx, s, group, id, afterID)
+	if err != nil {
+		return "", err
+	}
+	hi, err := anchor(ctx, s, group, id, beforeID)
+	if err != nil {
+		return "", err
+	}
+
+	// With a single anchor the other bound is its neighbour, which is missing at the ends of the group
+	switch {
+	case afterID != 0 && beforeID == 0:
+		hi, err = s.Next(ctx, group, lo, id)
+	case afterID == 0 && beforeID != 0:
+		lo, err = s.Prev(ctx, group, hi, id)
+	case afterID == 0 && beforeID == 0:
+		lo, err = s.Last(ctx, group, id)
+	}
+	if err != nil && !errs.IsNoRows(err) {
+		return "", err
+	}
+
+	key, err := Between(lo, hi)
+	if err != nil {
+		return "", ErrInvalidAnchor
+	}
+
+	return key, nil
+}
+
+// anchor returns the rank of an anchor, which must be another item in the group
+func anchor(ctx context.Context, s Store, group, id, anchorID int32) (string, error) {
+	if anchorID == 0 {
+		return "", nil
+	}
+	if anchorID == id {
+		return "", ErrInvalidAnchor
+	}
+
+	anchorGroup, key, err := s.Item(ctx, anchorID)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return "", ErrInvalidAnchor
+		}
+		return "", err
+	}
+	if anchorGroup != group {
+		return "", ErrInvalidAnchor
+	}
+
+	return key, nil
+}
+
+// Lock locks groups so items are placed in them one at a time.
+// Groups are locked in ID order so concurrent transactions cannot deadlock.
+func Lock(ctx context.Context, s Store, groups ...int32) error {
+	locked, err := s.Lock(ctx, groups)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range groups {
+		if !slices.Contains(locked, id) {
+			return ErrGroupNotFound
+		}
+	}
+
+	return nil
+}
+
+// Rebalance gives the items of each group new, evenly spaced keys, one group per transaction.
+// inTx runs fn in a new transaction with a Store bound to it. Groups deleted in the
+// meantime are skipped. It returns the number of groups that were rebalanced.
+func Rebalance(ctx context.Context, groups []int32, inTx func(fn func(Store) error) error) (int, error) {
+	rebalanced := 0
+	for _, group := range groups {
+		err := inTx(func(s Store) error {
+			if err := Lock(ctx, s, group); err != nil {
+				return err
+			}
+
+			ids, err := s.Ranked(ctx, group)
+			if err != nil {
+				return err
+			}
+
+			return s.SetRanks(ctx, ids, Spread(len(ids)))
+		})
+		if errors.Is(err, ErrGroupNotFound) {
+			continue
+		}
+		if err != nil {
+			return rebalanced, err
+		}
+		rebalanced++
+	}
+
+	return rebalanced, nil
+}
@@ -0,0 +1,200 @@
+package rank
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// memStore is an in-memory Store holding items in groups
+type memStore struct {
+	group map[int32]int32
+	key   map[int32]string
+}
+
+func newMemStore(groups map[int32][]int32) *memStore {
+	s := &memStore{group: map[int32]int32{}, key: map[int32]string{}}
+	for g, ids := range groups {
+		keys := Spread(len(ids))
+		for i, id := range ids {
+			s.group[id] = g
+			s.key[id] = keys[i]
+		}
+	}
+	return s
+}
+
+func (s *memStore) Item(ctx context.Context, id int32) (int32, string, error) {
+	g, ok := s.group[id]
+	if !ok {
+		return 0, "", pgx.ErrNoRows
+	}
+	return g, s.key[id], nil
+}
+
+// keys returns the keys of the group in order, skipping exclude
+func (s *memStore) keys(group, exclude int32) []string {
+	var keys []string
+	for id, g := range s.group {
+		if g == group && id != exclude {
+			keys = append(keys, s.key[id])
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *memStore) Next(ctx context.Context, group int32, key string, exclude int32) (string, error) {
+	for _, k := range s.keys(group, exclude) {
+		if k > key {
+			return k, nil
+		}
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (s *memStore) Prev(ctx context.Context, group int32, key string, exclude int32) (string, error) {
+	keys := s.keys(group, exclude)
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i] < key {
+			return keys[i], nil
+		}
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (s *memStore) Last(ctx context.Context, group, exclude int32) (string, error) {
+	keys := s.keys(group, exclude)
+	if len(keys) == 0 {
+		return "", pgx.ErrNoRows
+	}
+	return keys[len(keys)-1], nil
+}
+
+func (s *memStore) Lock(ctx context.Context, groups []int32) ([]int32, error) {
+	var locked []int32
+	for _, g := range groups {
+		for _, owner := range s.group {
+			if owner == g {
+				locked = append(locked, g)
+				break
+			}
+		}
+	}
+	return locked, nil
+}
+
+func (s *memStore) Ranked(ctx context.Context, group int32) ([]int32, error) {
+	var ids []int32
+	for id, g := range s.group {
+		if g == group {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return s.key[ids[i]] < s.key[ids[j]] })
+	return ids, nil
+}
+
+func (s *memStore) SetRanks(ctx context.Context, ids []int32, keys []string) error {
+	for i, id := range ids {
+		s.key[id] = keys[i]
+	}
+	return nil
+}
+
+func TestPlace(t *testing.T) {
+	ctx := context.Background()
+	// Group 1 holds 10, 11 and 12 in that order; group 2 holds 20
+	s := newMemStore(map[int32][]int32{1: {10, 11, 12}, 2: {20}})
+
+	tests := []struct {
+		name              string
+		id, after, before int32
+		wantLo, wantHi    int32
+		wantErr           error
+	}{
+		{name: "last", wantLo: 12},
+		{name: "after", after: 10, wantLo: 10, wantHi: 11},
+		{name: "after last", after: 12, wantLo: 12},
+		{name: "before", before: 11, wantLo: 10, wantHi: 11},
+		{name: "before first", before: 10, wantHi: 10},
+		{name: "between", after: 10, before: 11, wantLo: 10, wantHi: 11},
+		{name: "move skips itself", id: 11, after: 10, wantLo: 10, wantHi: 12},
+		{name: "move to end", id: 10, wantLo: 12},
+		{name: "anchor is the item", id: 11, after: 11, wantErr: ErrInvalidAnchor},
+		{name: "anchor in another group", after: 20, wantErr: ErrInvalidAnchor},
+		{name: "missing anchor", before: 99, wantErr: ErrInvalidAnchor},
+		{name: "anchors out of order", after: 12, before: 10, wantErr: ErrInvalidAnchor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Place(ctx, s, 1, tt.id, tt.after, tt.before)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Place() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if tt.wantLo != 0 && key <= s.key[tt.wantLo] {
+				t.Errorf("key %q is not after item %d (%q)", key, tt.wantLo, s.key[tt.wantLo])
+			}
+			if tt.wantHi != 0 && key >= s.key[tt.wantHi] {
+				t.Errorf("key %q is not before item %d (%q)", key, tt.wantHi, s.key[tt.wantHi])
+			}
+		})
+	}
+}
+
+func TestLock(t *testing.T) {
+	s := newMemStore(map[int32][]int32{1: {10}, 2: {20}})
+
+	if err := Lock(context.Background(), s, 1, 2); err != nil {
+		t.Errorf("Lock() error = %v", err)
+	}
+	if err := Lock(context.Background(), s, 1, 3); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("Lock() error = %v, want %v", err, ErrGroupNotFound)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(map[int32][]int32{1: {10, 11, 12}})
+
+	// Crowd the keys so they grow long, then spread them out again
+	for i := 0; i < 50; i++ {
+		key, err := Place(ctx, s, 1, 12, 10, 11)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.key[12] = key
+		s.key[11], s.key[12] = s.key[12], s.key[11]
+	}
+	if len(s.key[11]) < 5 {
+		t.Fatalf("keys did not grow: %q", s.key[11])
+	}
+	before, _ := s.Ranked(ctx, 1)
+
+	n, err := Rebalance(ctx, []int32{1, 3}, func(fn func(Store) error) error {
+		return fn(s)
+	})
+	if err != nil {
+		t.Fatalf("Rebalance() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Rebalance() = %d, want 1 as group 3 does not exist", n)
+	}
+
+	after, _ := s.Ranked(ctx, 1)
+	if !slices.Equal(before, after) {
+		t.Errorf("order changed from %v to %v", before, after)
+	}
+	for _, id := range after {
+		if len(s.key[id]) > 1 {
+			t.Errorf("item %d kept the long key %q", id, s.key[id])
+		}
+	}
+}
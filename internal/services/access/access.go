@@ -0,0 +1,134 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+)
+
+var (
+	// ErrNotFound is returned when the resource does not exist, is in the trash,
+	// or the user has no role on it, so that callers cannot probe for IDs they may not see.
+	ErrNotFound = errs.New(errs.NotFound, "not_found", "resource not found")
+	// ErrForbidden is returned when the user has a role on the resource that does not allow the action.
+	ErrForbidden = errs.New(errs.Forbidden, "forbidden", "you do not have access to this resource")
+)
+
+// Checker resolves the board that owns a resource and verifies the caller's role on it
+type Checker struct {
+	queries *db.Queries
+}
+
+// New creates a new access checker
+func New(queries *db.Queries) *Checker {
+	return &Checker{
+		queries: queries,
+	}
+}
+
+// Board verifies that the user may perform the action on the given board.
+// It returns the user's role on the board.
+func (c *Checker) Board(ctx context.Context, userID, boardID int32, action Action) (Role, error) {
+	return c.board(ctx, userID, boardID, action, false)
+}
+
+// DeletedBoard is like Board but also finds boards that are in the trash
+func (c *Checker) DeletedBoard(ctx context.Context, userID, boardID int32, action Action) (Role, error) {
+	return c.board(ctx, userID, boardID, action, true)
+}
+
+func (c *Checker) board(ctx context.Context, userID, boardID int32, action Action, includeDeleted bool) (Role, error) {
+	row, err := c.queries.GetBoardAccess(ctx, db.GetBoardAccessParams{
+		UserID:         userID,
+		ID:             boardID,
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		return "", notFoundOr(err, "board")
+	}
+
+	return authorize(effectiveRole(row.Role, row.WorkspaceRole, row.Visibility), action)
+}
+
+// List verifies that the user may perform the action on the board the list belongs to.
+// It returns the ID of that board.
+func (c *Checker) List(ctx context.Context, userID, listID int32, action Action) (int32, error) {
+	row, err := c.queries.GetListAccess(ctx, db.GetListAccessParams{
+		ID:     listID,
+		UserID: userID,
+	})
+	if err != nil {
+		return 0, notFoundOr(err, "list")
+	}
+
+	_, err = authorize(effectiveRole(row.Role, row.WorkspaceRole, row.Visibility), action)
+	return row.ID, err
+}
+
+// Card verifies that the user may perform the action on the board the card belongs to.
+// It returns the ID of that board.
+func (c *Checker) Card(ctx context.Context, userID, cardID int32, action Action) (int32, error) {
+	row, err := c.queries.GetCardAccess(ctx, db.GetCardAccessParams{
+		ID:     cardID,
+		UserID: userID,
+	})
+	if err != nil {
+		return 0, notFoundOr(err, "card")
+	}
+
+	_, err = authorize(effectiveRole(row.Role, row.WorkspaceRole, row.Visibility), action)
+	return row.ID, err
+}
+
+// Workspace verifies that the user is a member of the workspace.
+// It returns the user's role in the workspace.
+func (c *Checker) Workspace(ctx context.Context, userID, workspaceID int32) (WorkspaceRole, error) {
+	row, err := c.queries.GetWorkspaceAccess(ctx, db.GetWorkspaceAccessParams{
+		ID:     workspaceID,
+		UserID: userID,
+	})
+	if err != nil {
+		return "", notFoundOr(err, "workspace")
+	}
+
+	if row.Role == "" {
+		return "", ErrNotFound
+	}
+	return WorkspaceRole(row.Role), nil
+}
+
+// effectiveRole combines the user's board role with the access granted through the board's workspace.
+// Workspace admins manage every board in the workspace, and plain workspace members
+// can view boards that are visible to the workspace.
+func effectiveRole(boardRole, workspaceRole, visibility string) Role {
+	role := Role(boardRole)
+	switch {
+	case WorkspaceRole(workspaceRole).IsAdmin():
+		return higher(role, RoleAdmin)
+	case workspaceRole != "" && visibility == VisibilityWorkspace:
+		return higher(role, RoleViewer)
+	}
+	return role
+}
+
+// authorize checks that the role allows the action.
+// An empty role means the user is not a member of the board, which is reported as ErrNotFound.
+func authorize(role Role, action Action) (Role, error) {
+	if role == "" {
+		return role, ErrNotFound
+	}
+	if !role.Can(action) {
+		return role, ErrForbidden
+	}
+	return role, nil
+}
+
+// notFoundOr translates a missing row into ErrNotFound and wraps any other error
+func notFoundOr(err error, resource string) error {
+	if errs.IsNoRows(err) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("failed to check %s access: %w", resource, err)
+}
@@ -0,0 +1,100 @@
+package access
+
+import "fmt"
+
+// Role is a member's role on a board
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Board visibility settings
+const (
+	// VisibilityPrivate boards are only visible to their members
+	VisibilityPrivate = "private"
+	// VisibilityWorkspace boards are visible to every member of their workspace
+	VisibilityWorkspace = "workspace"
+)
+
+// WorkspaceRole is a member's role in a workspace
+type WorkspaceRole string
+
+const (
+	WorkspaceOwner  WorkspaceRole = "owner"
+	WorkspaceAdmin  WorkspaceRole = "admin"
+	WorkspaceMember WorkspaceRole = "member"
+)
+
+// Action is something a board member can do
+type Action int
+
+const (
+	// ViewBoard allows reading the board, its lists and its cards
+	ViewBoard Action = iota
+	// EditCards allows creating, updating and moving cards
+	EditCards
+	// DeleteCards allows deleting cards
+	DeleteCards
+	// EditLists allows creating and updating lists
+	EditLists
+	// DeleteLists allows deleting lists
+	DeleteLists
+	// ManageBoard allows updating the board and managing its members
+	ManageBoard
+	// DeleteBoard allows deleting the board
+	DeleteBoard
+)
+
+// permissions lists the actions allowed for each role
+var permissions = map[Role][]Action{
+	RoleOwner:  {ViewBoard, EditCards, DeleteCards, EditLists, DeleteLists, ManageBoard, DeleteBoard},
+	RoleAdmin:  {ViewBoard, EditCards, DeleteCards, EditLists, DeleteLists, ManageBoard},
+	RoleEditor: {ViewBoard, EditCards, DeleteCards, EditLists},
+	RoleViewer: {ViewBoard},
+}
+
+// ParseRole converts a string into a Role, rejecting unknown roles
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := permissions[role]; !ok {
+		return "", fmt.Errorf("invalid role %q", s)
+	}
+	return role, nil
+}
+
+// ParseWorkspaceRole converts a string into a WorkspaceRole, rejecting unknown roles
+func ParseWorkspaceRole(s string) (WorkspaceRole, error) {
+	role := WorkspaceRole(s)
+	switch role {
+	case WorkspaceOwner, WorkspaceAdmin, WorkspaceMember:
+		return role, nil
+	}
+	return "", fmt.Errorf("invalid workspace role %q", s)
+}
+
+// IsAdmin reports whether the role can manage the workspace and its boards
+func (r WorkspaceRole) IsAdmin() bool {
+	return r == WorkspaceOwner || r == WorkspaceAdmin
+}
+
+// Can reports whether the role allows the action
+func (r Role) Can(action Action) bool {
+	for _, a := range permissions[r] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// higher returns the more powerful of two roles
+func higher(a, b Role) Role {
+	if len(permissions[a]) >= len(permissions[b]) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,204 @@
+package accesstoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scope limits what a personal access token may do
+type Scope string
+
+const (
+	// ScopeRead allows read-only requests
+	ScopeRead Scope = "read"
+	// ScopeWrite allows requests that change data
+	ScopeWrite Scope = "write"
+	// ScopeAdmin allows managing the account itself, such as its tokens and sessions.
+	// It is also needed to delete, archive, unarchive or restore boards, workspaces,
+	// lists and cards, so a leaked write token cannot wipe or resurrect content.
+	ScopeAdmin Scope = "admin"
+)
+
+// Prefix marks personal access tokens so they are easy to recognise, e.g. by secret scanners
+const Prefix = "gbp_"
+
+const (
+	// DefaultTTL is used when no expiry is requested
+	DefaultTTL = 90 * 24 * time.Hour
+	// MaxTTL is the longest a token may live
+	MaxTTL = 365 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidToken is returned when a token is unknown or expired
+	ErrInvalidToken = errs.New(errs.Unauthorized, "invalid_access_token", "invalid or expired access token")
+	// ErrTokenNotFound is returned when the token does not exist or belongs to another user
+	ErrTokenNotFound = errs.New(errs.NotFound, "token_not_found", "access token not found")
+	// ErrEmptyName is returned when the token has no name
+	ErrEmptyName = errs.Invalid("name", "token name cannot be empty")
+	// ErrInvalidScopes is returned for missing or unknown scopes
+	ErrInvalidScopes = errs.Invalid("scopes", "scopes must be one or more of read, write or admin")
+	// ErrInvalidExpiry is returned when the requested lifetime is out of range
+	ErrInvalidExpiry = errs.Invalid("expires_in_days", "expiry must be between 1 and 365 days")
+)
+
+// Service manages personal access tokens used by scripts and CI
+type Service struct {
+	queries *db.Queries
+}
+
+// New creates a new access token service
+func New(queries *db.Queries) *Service {
+	return &Service{
+		queries: queries,
+	}
+}
+
+// Create issues a new token for the user.
+// The plain token is returned only once; just its hash is stored.
+func (s *Service) Create(ctx context.Context, userID int32, name string, scopes []string, ttl time.Duration) (string, *db.PersonalAccessToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrEmptyName
+	}
+
+	scopes, err := parseScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if ttl < 0 || ttl > MaxTTL {
+		return "", nil, ErrInvalidExpiry
+	}
+
+	plain, _, err := token.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+	plain = Prefix + plain
+
+	pat, err := s.queries.CreatePersonalAccessToken(ctx, db.CreatePersonalAccessTokenParams{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: token.Hash(plain),
+		Scopes:    scopes,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return plain, &pat, nil
+}
+
+// List gets the user's tokens, without their hashes
+func (s *Service) List(ctx context.Context, userID int32) ([]db.GetPersonalAccessTokensRow, error) {
+	tokens, err := s.queries.GetPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access tokens: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if tokens == nil {
+		return []db.GetPersonalAccessTokensRow{}, nil
+	}
+
+	return tokens, nil
+}
+
+// Revoke deletes one of the user's tokens
+func (s *Service) Revoke(ctx context.Context, userID, tokenID int32) error {
+	rows, err := s.queries.DeletePersonalAccessToken(ctx, db.DeletePersonalAccessTokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	if rows == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeAll deletes every token of the user. It is exported so other services
+// can revoke tokens inside their own transactions.
+func RevokeAll(ctx context.Context, q *db.Queries, userID int32) error {
+	return q.DeleteUserPersonalAccessTokens(ctx, userID)
+}
+
+// Authenticate looks up a plain token and records that it was used
+func (s *Service) Authenticate(ctx context.Context, plain string) (*db.PersonalAccessToken, error) {
+	if !strings.HasPrefix(plain, Prefix) {
+		return nil, ErrInvalidToken
+	}
+
+	pat, err := s.queries.GetPersonalAccessTokenByHash(ctx, token.Hash(plain))
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	if err := s.queries.TouchPersonalAccessToken(ctx, pat.ID); err != nil {
+		return nil, fmt.Errorf("failed to update access token: %w", err)
+	}
+
+	return &pat, nil
+}
+
+// Allows reports whether the scopes grant the required scope.
+// Admin implies write, and write implies read.
+func Allows(scopes []string, required Scope) bool {
+	for _, s := range scopes {
+		if rank(Scope(s)) >= rank(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// rank orders scopes from least to most powerful
+func rank(s Scope) int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeWrite:
+		return 2
+	case ScopeAdmin:
+		return 3
+	}
+	return 0
+}
+
+// parseScopes validates and de-duplicates the requested scopes
+func parseScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScopes
+	}
+
+	seen := make(map[string]bool)
+	parsed := []string{}
+	for _, s := range scopes {
+		if rank(Scope(s)) == 0 {
+			return nil, ErrInvalidScopes
+		}
+		if !seen[s] {
+			seen[s] = true
+			parsed = append(parsed, s)
+		}
+	}
+	return parsed, nil
+}
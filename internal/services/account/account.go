@@ -0,0 +1,174 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/session"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrWrongPassword is returned when the password given to confirm the deletion does not match
+var ErrWrongPassword = errs.New(errs.Forbidden, "wrong_password", "password is incorrect")
+
+// OwnedResource is a board or workspace that blocks the account deletion
+type OwnedResource struct {
+	ID     int32  `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// OwnershipError is returned when the user owns boards or workspaces that other
+// members still use and no valid new owner was given for them.
+type OwnershipError struct {
+	Boards     []OwnedResource `json:"boards"`
+	Workspaces []OwnedResource `json:"workspaces"`
+}
+
+func (e *OwnershipError) Error() string {
+	return "some boards or workspaces need a new owner before the account can be deleted"
+}
+
+// Transfers maps board or workspace IDs to the member who should become the new owner
+type Transfers struct {
+	Boards     map[int32]int32
+	Workspaces map[int32]int32
+}
+
+// Service handles self-service account export and deletion
+type Service struct {
+	queries *db.Queries
+}
+
+// New creates a new account service
+func New(queries *db.Queries) *Service {
+	return &Service{
+		queries: queries,
+	}
+}
+
+// Delete removes the user's account after checking their password. Accounts created
+// through single sign-on that never set a password have none to check.
+// Boards and workspaces the user owns that other users can reach are handed over
+// according to transfers; if any of them has no valid new owner, nothing is
+// deleted and an *OwnershipError lists them. A board is reachable by its members,
+// its workspace's owners and admins, and for workspace-visible boards every
+// workspace member. Boards and workspaces nobody else can reach are deleted with the account.
+func (s *Service) Delete(ctx context.Context, userID int32, password string, transfers Transfers) error {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.HasPassword {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password)); err != nil {
+			return ErrWrongPassword
+		}
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		ownership := &OwnershipError{
+			Boards:     []OwnedResource{},
+			Workspaces: []OwnedResource{},
+		}
+
+		boards, err := q.GetOwnedBoards(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, b := range boards {
+			if b.OtherMembers == 0 {
+				continue
+			}
+			reason, err := transfer(transfers.Boards, b.ID, userID, func(newOwnerID int32) (int64, error) {
+				return q.TransferBoardOwnership(ctx, db.TransferBoardOwnershipParams{
+					BoardID:    b.ID,
+					NewOwnerID: newOwnerID,
+				})
+			})
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				ownership.Boards = append(ownership.Boards, OwnedResource{ID: b.ID, Name: b.Name, Reason: reason})
+			}
+		}
+
+		workspaces, err := q.GetOwnedWorkspaces(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, w := range workspaces {
+			if w.OtherMembers == 0 {
+				continue
+			}
+			reason, err := transfer(transfers.Workspaces, w.ID, userID, func(newOwnerID int32) (int64, error) {
+				return q.TransferWorkspaceOwnership(ctx, db.TransferWorkspaceOwnershipParams{
+					WorkspaceID: w.ID,
+					NewOwnerID:  newOwnerID,
+				})
+			})
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				ownership.Workspaces = append(ownership.Workspaces, OwnedResource{ID: w.ID, Name: w.Name, Reason: reason})
+			}
+		}
+
+		if len(ownership.Boards) > 0 || len(ownership.Workspaces) > 0 {
+			return ownership
+		}
+
+		// End every session before the user row, and with it the session index, disappears
+		if err := session.RevokeAll(ctx, q, userID, ""); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUser(ctx, userID); err != nil {
+			return err
+		}
+
+		return q.CreateSecurityEvent(ctx, db.CreateSecurityEventParams{
+			UserID:    pgtype.Int4{},
+			EventType: "account_deleted",
+			Email:     user.Email,
+			Details:   fmt.Sprintf("user %d deleted their account", userID),
+		})
+	})
+	if err != nil {
+		var ownership *OwnershipError
+		if errors.As(err, &ownership) {
+			return ownership
+		}
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	return nil
+}
+
+// transfer hands a resource to the new owner chosen in transfers.
+// It returns a reason when the resource still needs a new owner.
+func transfer(transfers map[int32]int32, id, userID int32, apply func(newOwnerID int32) (int64, error)) (string, error) {
+	newOwnerID, ok := transfers[id]
+	if !ok {
+		return "needs a new owner", nil
+	}
+	if newOwnerID == userID {
+		return "the new owner must be another member", nil
+	}
+
+	rows, err := apply(newOwnerID)
+	if err != nil {
+		return "", err
+	}
+	if rows == 0 {
+		return "the new owner must already be a member", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,143 @@
+package account
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Profile is the exported part of the user row. Password hashes and 2FA secrets are left out.
+type Profile struct {
+	ID               int32              `json:"id"`
+	Name             string             `json:"name"`
+	Email            string             `json:"email"`
+	EmailVerifiedAt  pgtype.Timestamptz `json:"email_verified_at"`
+	TwoFactorEnabled bool               `json:"two_factor_enabled"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+// Export is a copy of the personal data held about a user.
+// Boards, lists and cards cover every board the user is a member of.
+type Export struct {
+	ExportedAt     time.Time                       `json:"exported_at"`
+	Profile        Profile                         `json:"profile"`
+	Boards         []db.GetExportBoardsRow         `json:"boards"`
+	Lists          []db.List                       `json:"lists"`
+	Cards          []db.Card                       `json:"cards"`
+	Workspaces     []db.GetWorkspacesByUserRow     `json:"workspaces"`
+	Sessions       []db.GetUserSessionsRow         `json:"sessions"`
+	AccessTokens   []db.GetPersonalAccessTokensRow `json:"access_tokens"`
+	Identities     []db.GetUserIdentitiesRow       `json:"identities"`
+	SecurityEvents []db.GetUserSecurityEventsRow   `json:"security_events"`
+}
+
+// Export collects the user's personal data. It runs in one transaction so the parts are consistent.
+func (s *Service) Export(ctx context.Context, userID int32) (*Export, error) {
+	export := &Export{ExportedAt: time.Now().UTC()}
+
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		user, err := q.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		export.Profile = Profile{
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			EmailVerifiedAt:  user.EmailVerifiedAt,
+			TwoFactorEnabled: user.TotpEnabledAt.Valid,
+			CreatedAt:        user.CreatedAt,
+		}
+
+		if export.Boards, err = q.GetExportBoards(ctx, userID); err != nil {
+			return err
+		}
+		if export.Lists, err = q.GetExportLists(ctx, userID); err != nil {
+			return err
+		}
+		if export.Cards, err = q.GetExportCards(ctx, userID); err != nil {
+			return err
+		}
+		if export.Workspaces, err = q.GetWorkspacesByUser(ctx, userID); err != nil {
+			return err
+		}
+		if export.Sessions, err = q.GetUserSessions(ctx, db.GetUserSessionsParams{UserID: userID}); err != nil {
+			return err
+		}
+		if export.AccessTokens, err = q.GetPersonalAccessTokens(ctx, userID); err != nil {
+			return err
+		}
+		if export.Identities, err = q.GetUserIdentities(ctx, userID); err != nil {
+			return err
+		}
+		export.SecurityEvents, err = q.GetUserSecurityEvents(ctx, pgtype.Int4{Int32: userID, Valid: true})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export account: %w", err)
+	}
+
+	// Ensure we return empty lists instead of null
+	export.Boards = orEmpty(export.Boards)
+	export.Lists = orEmpty(export.Lists)
+	export.Cards = orEmpty(export.Cards)
+	export.Workspaces = orEmpty(export.Workspaces)
+	export.Sessions = orEmpty(export.Sessions)
+	export.AccessTokens = orEmpty(export.AccessTokens)
+	export.Identities = orEmpty(export.Identities)
+	export.SecurityEvents = orEmpty(export.SecurityEvents)
+
+	return export, nil
+}
+
+// orEmpty replaces a nil slice with an empty one
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// WriteZip writes the export as a ZIP archive with one JSON file per section
+func (e *Export) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.Profile},
+		{"boards.json", e.Boards},
+		{"lists.json", e.Lists},
+		{"cards.json", e.Cards},
+		{"workspaces.json", e.Workspaces},
+		{"sessions.json", e.Sessions},
+		{"access_tokens.json", e.AccessTokens},
+		{"identities.json", e.Identities},
+		{"security_events.json", e.SecurityEvents},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: e.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to export: %w", f.name, err)
+		}
+
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return fmt.Errorf("failed to write %s to export: %w", f.name, err)
+		}
+	}
+
+	return zw.Close()
+}
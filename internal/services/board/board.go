@@ -3,35 +3,74 @@ package board
 import (
 	"context"
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var (
+	// ErrEmptyName is returned when the board name is empty
+	ErrEmptyName = errs.Invalid("name", "board name cannot be empty")
+	// ErrInvalidName is returned when the board name contains control characters like line breaks
+	ErrInvalidName = errs.Invalid("name", "board name cannot contain control characters")
+	// ErrInvalidVisibility is returned for unknown visibilities or workspace visibility without a workspace
+	ErrInvalidVisibility = errs.Invalid("visibility", "visibility must be private, or workspace for boards in a workspace")
+	// ErrNotArchived is returned when deleting a board that has not been archived
+	ErrNotArchived = errs.New(errs.Conflict, "not_archived", "board must be archived before it can be deleted")
+)
+
 // Service handles board-related business logic
 type Service struct {
 	queries *db.Queries
+	access  *access.Checker
 }
 
 // New creates a new board service
 func New(queries *db.Queries) *Service {
 	return &Service{
 		queries: queries,
+		access:  access.New(queries),
 	}
 }
 
-// CreateBoard creates a new board for a user
-func (s *Service) CreateBoard(ctx context.Context, name, description string, userID int32) (*db.Board, error) {
+// CreateBoard creates a new board for a user.
+// A workspaceID of 0 creates a personal board; otherwise the user must be a member of the workspace.
+func (s *Service) CreateBoard(ctx context.Context, name, description string, userID, workspaceID int32, visibility string) (*db.Board, error) {
 	// Validate Input
-	if name == "" {
-		return nil, fmt.Errorf("board name cannot be empty")
+	if err := checkName(name); err != nil {
+		return nil, err
 	}
 
-	// Create a board
-	board, err := s.queries.CreateBoard(ctx, db.CreateBoardParams{
-		Name:        name,
-		Description: pgtype.Text{String: description, Valid: true},
-		CreatedBy:   userID,
+	workspace, visibility, err := s.checkWorkspace(ctx, userID, workspaceID, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the board and make its creator the owner
+	var board db.Board
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		board, err = q.CreateBoard(ctx, db.CreateBoardParams{
+			Name:        name,
+			Description: pgtype.Text{String: description, Valid: true},
+			CreatedBy:   userID,
+			WorkspaceID: workspace,
+			Visibility:  visibility,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateBoardMember(ctx, db.CreateBoardMemberParams{
+			BoardID: board.ID,
+			UserID:  userID,
+			Role:    string(access.RoleOwner),
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create board: %w", err)
@@ -40,9 +79,13 @@ func (s *Service) CreateBoard(ctx context.Context, name, description string, use
 	return &board, nil
 }
 
-// GetUserBoards gets all boards for a specific user
-func (s *Service) GetUserBoards(ctx context.Context, userID int32) ([]db.Board, error) {
-	boards, err := s.queries.GetBoardsByUser(ctx, userID)
+// GetUserBoards gets all boards the user is a member of.
+// Archived boards are listed only when archived is true, and then only those.
+func (s *Service) GetUserBoards(ctx context.Context, userID int32, archived bool) ([]db.Board, error) {
+	boards, err := s.queries.GetBoardsByUser(ctx, db.GetBoardsByUserParams{
+		UserID:   userID,
+		Archived: archived,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fet user boards; %w", err)
 	}
@@ -56,9 +99,16 @@ func (s *Service) GetUserBoards(ctx context.Context, userID int32) ([]db.Board,
 }
 
 // GetBoardById gets a sinle board by ID
-func (s *Service) GetBoardByID(ctx context.Context, boardID int32) (*db.Board, error) {
+func (s *Service) GetBoardByID(ctx context.Context, userID, boardID int32) (*db.Board, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
 	board, err := s.queries.GetBoardByID(ctx, boardID)
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to get board: %w", err)
 	}
 
@@ -66,10 +116,14 @@ func (s *Service) GetBoardByID(ctx context.Context, boardID int32) (*db.Board, e
 }
 
 // UpdateBoard updates a board's name and description
-func (s *Service) UpdateBoard(ctx context.Context, boardID int32, name, description string) (*db.Board, error) {
+func (s *Service) UpdateBoard(ctx context.Context, userID, boardID int32, name, description string) (*db.Board, error) {
 	// Validate input
-	if name == "" {
-		return nil, fmt.Errorf("board name cannot be empty")
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return nil, err
 	}
 
 	board, err := s.queries.UpdateBoard(ctx, db.UpdateBoardParams{
@@ -78,18 +132,144 @@ func (s *Service) UpdateBoard(ctx context.Context, boardID int32, name, descript
 		ID:          boardID,
 	})
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to update board: %w", err)
 	}
 
 	return &board, nil
 }
 
-// DeleteBoard deletes a board
-func (s *Service) DeleteBoard(ctx context.Context, boardID int32) error {
-	err := s.queries.DeleteBoard(ctx, boardID)
+// SetWorkspace moves a board into a workspace, or out of it when workspaceID is 0,
+// and sets who in the workspace can see it. Admins may change the visibility, but
+// only the owner may move the board, since that changes whose admins control it.
+func (s *Service) SetWorkspace(ctx context.Context, userID, boardID, workspaceID int32, visibility string) (*db.Board, error) {
+	workspace, visibility, err := s.checkWorkspace(ctx, userID, workspaceID, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.access.Board(ctx, userID, boardID, access.ManageBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	// Workspace members who can no longer see the board lose their cards on it
+	var board db.Board
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		// The board is locked so it cannot be moved meanwhile and slip past the owner check
+		locked, err := q.LockBoards(ctx, []int32{boardID})
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return access.ErrNotFound
+		}
+		current, err := q.GetBoardByID(ctx, boardID)
+		if err != nil {
+			return err
+		}
+		if current.WorkspaceID != workspace && !role.Can(access.DeleteBoard) {
+			return access.ErrForbidden
+		}
+
+		board, err = q.UpdateBoardWorkspace(ctx, db.UpdateBoardWorkspaceParams{
+			WorkspaceID: workspace,
+			Visibility:  visibility,
+			ID:          boardID,
+		})
+		if err != nil {
+			return err
+		}
+		return q.UnassignUsersWithoutAccess(ctx, []int32{boardID})
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		if errs.KindOf(err) != errs.Internal {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update board workspace: %w", err)
+	}
+
+	return &board, nil
+}
+
+// SetArchived archives a board, or restores it when archived is false.
+// Archiving an archived board keeps its original archive time.
+func (s *Service) SetArchived(ctx context.Context, userID, boardID int32, archived bool) (*db.Board, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.DeleteBoard); err != nil {
+		return nil, err
+	}
+
+	board, err := s.queries.SetBoardArchived(ctx, db.SetBoardArchivedParams{
+		Archived: archived,
+		ID:       boardID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to archive board: %w", err)
+	}
+
+	return &board, nil
+}
+
+// DeleteBoard moves an archived board to the user's trash
+func (s *Service) DeleteBoard(ctx context.Context, userID, boardID int32) error {
+	if _, err := s.access.Board(ctx, userID, boardID, access.DeleteBoard); err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteBoard(ctx, db.DeleteBoardParams{
+		DeletedBy: pgtype.Int4{Int32: userID, Valid: true},
+		ID:        boardID,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete board: %w", err)
 	}
+	if rows == 0 {
+		return ErrNotArchived
+	}
+
+	return nil
+}
+
+// checkWorkspace validates the workspace and visibility of a board.
+// The user must be a member of the workspace to place boards in it.
+func (s *Service) checkWorkspace(ctx context.Context, userID, workspaceID int32, visibility string) (pgtype.Int4, string, error) {
+	if visibility == "" {
+		visibility = access.VisibilityPrivate
+	}
+	if visibility != access.VisibilityPrivate && visibility != access.VisibilityWorkspace {
+		return pgtype.Int4{}, "", ErrInvalidVisibility
+	}
+
+	if workspaceID == 0 {
+		if visibility == access.VisibilityWorkspace {
+			return pgtype.Int4{}, "", ErrInvalidVisibility
+		}
+		return pgtype.Int4{}, visibility, nil
+	}
 
+	if _, err := s.access.Workspace(ctx, userID, workspaceID); err != nil {
+		return pgtype.Int4{}, "", err
+	}
+
+	return pgtype.Int4{Int32: workspaceID, Valid: true}, visibility, nil
+}
+
+// checkName validates a board name. Names end up in email subjects, so line breaks
+// and other control characters are rejected.
+func checkName(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return ErrInvalidName
+	}
 	return nil
 }
@@ -0,0 +1,143 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+var (
+	// ErrEmptyLabelName is returned when the label name is empty
+	ErrEmptyLabelName = errs.Invalid("name", "label name cannot be empty")
+	// ErrInvalidColor is returned when the label color is not a hex color
+	ErrInvalidColor = errs.Invalid("color", "color must be a hex color like #61bd4f")
+	// ErrDuplicateLabel is returned when the board already has a label with the name
+	ErrDuplicateLabel = errs.New(errs.Conflict, "duplicate_label", "board already has a label with this name")
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// GetLabels gets the labels of a board, by name
+func (s *Service) GetLabels(ctx context.Context, userID, boardID int32) ([]db.Label, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	labels, err := s.queries.GetLabelsByBoard(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board labels: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if labels == nil {
+		return []db.Label{}, nil
+	}
+
+	return labels, nil
+}
+
+// CreateLabel adds a label to a board's palette
+func (s *Service) CreateLabel(ctx context.Context, userID, boardID int32, name, color string) (*db.Label, error) {
+	color, err := checkLabel(name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.EditLists); err != nil {
+		return nil, err
+	}
+
+	label, err := s.queries.CreateLabel(ctx, db.CreateLabelParams{
+		BoardID: boardID,
+		Name:    name,
+		Color:   color,
+	})
+	if err != nil {
+		if errs.IsUniqueViolation(err) {
+			return nil, ErrDuplicateLabel
+		}
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return &label, nil
+}
+
+// UpdateLabel changes the name and color of a label on a board
+func (s *Service) UpdateLabel(ctx context.Context, userID, boardID, labelID int32, name, color string) (*db.Label, error) {
+	color, err := checkLabel(name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBoardLabel(ctx, userID, boardID, labelID, access.EditLists); err != nil {
+		return nil, err
+	}
+
+	label, err := s.queries.UpdateLabel(ctx, db.UpdateLabelParams{
+		Name:  name,
+		Color: color,
+		ID:    labelID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		if errs.IsUniqueViolation(err) {
+			return nil, ErrDuplicateLabel
+		}
+		return nil, fmt.Errorf("failed to update label: %w", err)
+	}
+
+	return &label, nil
+}
+
+// DeleteLabel deletes a label from a board and removes it from every card
+func (s *Service) DeleteLabel(ctx context.Context, userID, boardID, labelID int32) error {
+	if err := s.checkBoardLabel(ctx, userID, boardID, labelID, access.DeleteLists); err != nil {
+		return err
+	}
+
+	if err := s.queries.DeleteLabel(ctx, labelID); err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	return nil
+}
+
+// checkBoardLabel verifies that the label belongs to the board and that the user may perform the action on it
+func (s *Service) checkBoardLabel(ctx context.Context, userID, boardID, labelID int32, action access.Action) error {
+	if _, err := s.access.Board(ctx, userID, boardID, action); err != nil {
+		return err
+	}
+
+	label, err := s.queries.GetLabelByID(ctx, labelID)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return access.ErrNotFound
+		}
+		return fmt.Errorf("failed to get label: %w", err)
+	}
+	if label.BoardID != boardID {
+		return access.ErrNotFound
+	}
+
+	return nil
+}
+
+// checkLabel validates a label name and color, and returns the color in lower case
+func checkLabel(name, color string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", ErrEmptyLabelName
+	}
+
+	color = strings.ToLower(color)
+	if !colorPattern.MatchString(color) {
+		return "", ErrInvalidColor
+	}
+	return color, nil
+}
@@ -0,0 +1,165 @@
+package board
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+var (
+	// ErrUserNotFound is returned when the user being added does not exist
+	ErrUserNotFound = errs.New(errs.NotFound, "user_not_found", "user not found")
+	// ErrMemberNotFound is returned when the user is not a member of the board
+	ErrMemberNotFound = errs.New(errs.NotFound, "member_not_found", "user is not a member of this board")
+	// ErrAlreadyMember is returned when the user is already a member of the board
+	ErrAlreadyMember = errs.New(errs.Conflict, "already_member", "user is already a member of this board")
+	// ErrInvalidRole is returned for unknown roles or when trying to grant ownership
+	ErrInvalidRole = errs.Invalid("role", "role must be one of admin, editor or viewer")
+	// ErrOwnerImmutable is returned when trying to change or remove the board owner
+	ErrOwnerImmutable = errs.New(errs.Conflict, "owner_immutable", "the board owner cannot be changed or removed")
+)
+
+// GetMembers gets all members of a board
+func (s *Service) GetMembers(ctx context.Context, userID, boardID int32) ([]db.GetBoardMembersRow, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	members, err := s.queries.GetBoardMembers(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board members: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if members == nil {
+		return []db.GetBoardMembersRow{}, nil
+	}
+
+	return members, nil
+}
+
+// AddMember adds an existing user, looked up by email, to a board with the given role
+func (s *Service) AddMember(ctx context.Context, userID, boardID int32, email, role string) (*db.BoardMember, error) {
+	memberRole, err := parseMemberRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return nil, err
+	}
+
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	member, err := s.queries.CreateBoardMember(ctx, db.CreateBoardMemberParams{
+		BoardID: boardID,
+		UserID:  user.ID,
+		Role:    string(memberRole),
+	})
+	if err != nil {
+		if errs.IsUniqueViolation(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, fmt.Errorf("failed to add board member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// UpdateMemberRole changes the role of a board member
+func (s *Service) UpdateMemberRole(ctx context.Context, userID, boardID, memberID int32, role string) (*db.BoardMember, error) {
+	memberRole, err := parseMemberRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotOwner(ctx, boardID, memberID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.queries.UpdateBoardMemberRole(ctx, db.UpdateBoardMemberRoleParams{
+		Role:    string(memberRole),
+		BoardID: boardID,
+		UserID:  memberID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update board member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// RemoveMember removes a member from a board.
+// Members can always remove themselves; removing others requires the ManageBoard permission.
+func (s *Service) RemoveMember(ctx context.Context, userID, boardID, memberID int32) error {
+	action := access.ManageBoard
+	if userID == memberID {
+		action = access.ViewBoard
+	}
+	if _, err := s.access.Board(ctx, userID, boardID, action); err != nil {
+		return err
+	}
+
+	if err := s.checkNotOwner(ctx, boardID, memberID); err != nil {
+		return err
+	}
+
+	// Cards on the board can only stay assigned to the member through the workspace
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		err := q.DeleteBoardMember(ctx, db.DeleteBoardMemberParams{
+			BoardID: boardID,
+			UserID:  memberID,
+		})
+		if err != nil {
+			return err
+		}
+		return q.UnassignUsersWithoutAccess(ctx, []int32{boardID})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove board member: %w", err)
+	}
+
+	return nil
+}
+
+// checkNotOwner makes sure the member exists and is not the board owner
+func (s *Service) checkNotOwner(ctx context.Context, boardID, memberID int32) error {
+	member, err := s.queries.GetBoardMember(ctx, db.GetBoardMemberParams{
+		BoardID: boardID,
+		UserID:  memberID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("failed to get board member: %w", err)
+	}
+
+	if access.Role(member.Role) == access.RoleOwner {
+		return ErrOwnerImmutable
+	}
+
+	return nil
+}
+
+// parseMemberRole validates a role that can be granted to a member
+func parseMemberRole(role string) (access.Role, error) {
+	r, err := access.ParseRole(role)
+	if err != nil || r == access.RoleOwner {
+		return "", ErrInvalidRole
+	}
+	return r, nil
+}
@@ -0,0 +1,211 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+// includeOptions are the values accepted by ParseInclude
+var includeOptions = []string{"labels", "assignees", "members"}
+
+var (
+	// ErrInvalidInclude is returned for unknown values in the include parameter
+	ErrInvalidInclude = errs.Invalid("include", "include must be a comma-separated list of: "+strings.Join(includeOptions, ", "))
+	// ErrUnsupportedInclude is returned for checklists and comments, which boards do not have yet
+	ErrUnsupportedInclude = errs.Invalid("include", "checklists and comments cannot be included because cards do not have them yet")
+)
+
+// Include selects the optional data embedded in a board snapshot
+type Include struct {
+	// Labels embeds the board labels and the labels of each card
+	Labels bool
+	// Assignees embeds the assignees of each card
+	Assignees bool
+	// Members embeds the board members
+	Members bool
+}
+
+// ParseInclude parses a comma-separated list of optional data, like "labels,members"
+func ParseInclude(s string) (Include, error) {
+	var include Include
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "labels":
+			include.Labels = true
+		case "assignees":
+			include.Assignees = true
+		case "members":
+			include.Members = true
+		case "checklists", "comments":
+			return Include{}, ErrUnsupportedInclude
+		default:
+			return Include{}, ErrInvalidInclude
+		}
+	}
+	return include, nil
+}
+
+// Snapshot is a board with its lists and their cards in order.
+// Optional data is left out of the JSON unless it was included.
+type Snapshot struct {
+	db.Board
+	Labels  []db.Label `json:",omitzero"`
+	Lists   []SnapshotList
+	Members []db.GetBoardMembersRow `json:",omitzero"`
+}
+
+// SnapshotList is a list with its cards, in order
+type SnapshotList struct {
+	db.List
+	Cards []SnapshotCard
+}
+
+// SnapshotCard is a card with its optional labels and assignees
+type SnapshotCard struct {
+	db.Card
+	Labels    []db.Label               `json:",omitzero"`
+	Assignees []db.GetCardAssigneesRow `json:",omitzero"`
+}
+
+// GetSnapshot gets a board with all its lists and cards, plus the optional data in include.
+// When labelIDs is not empty only cards with any of those labels are included.
+// It runs one query per kind of data, however many lists the board has.
+func (s *Service) GetSnapshot(ctx context.Context, userID, boardID int32, include Include, labelIDs []int32) (*Snapshot, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	board, err := s.queries.GetBoardByID(ctx, boardID)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+
+	// Archived lists and cards are left out
+	lists, err := s.queries.GetListsByBoard(ctx, db.GetListsByBoardParams{
+		BoardID:  boardID,
+		Archived: false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board lists: %w", err)
+	}
+
+	cards, err := s.queries.GetCardsByBoard(ctx, db.GetCardsByBoardParams{
+		BoardID:  boardID,
+		LabelIds: labelIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board cards: %w", err)
+	}
+
+	// Cards come sorted by list, then rank
+	cardsByList := make(map[int32][]SnapshotCard, len(lists))
+	for _, card := range cards {
+		cardsByList[card.ListID] = append(cardsByList[card.ListID], SnapshotCard{Card: card})
+	}
+
+	snapshot := &Snapshot{
+		Board: board,
+		Lists: make([]SnapshotList, len(lists)),
+	}
+	for i, list := range lists {
+		snapshot.Lists[i] = SnapshotList{List: list, Cards: cardsByList[list.ID]}
+
+		// Ensure we return an empty slice instead of nil
+		if snapshot.Lists[i].Cards == nil {
+			snapshot.Lists[i].Cards = []SnapshotCard{}
+		}
+	}
+
+	if include.Labels {
+		if err := s.includeLabels(ctx, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	if include.Assignees {
+		if err := s.includeAssignees(ctx, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	if include.Members {
+		snapshot.Members, err = s.queries.GetBoardMembers(ctx, boardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get board members: %w", err)
+		}
+		if snapshot.Members == nil {
+			snapshot.Members = []db.GetBoardMembersRow{}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// eachCard calls fn for every card in the snapshot
+func (snapshot *Snapshot) eachCard(fn func(card *SnapshotCard)) {
+	for i := range snapshot.Lists {
+		for j := range snapshot.Lists[i].Cards {
+			fn(&snapshot.Lists[i].Cards[j])
+		}
+	}
+}
+
+// includeLabels adds the board labels and the labels of each card to the snapshot
+func (s *Service) includeLabels(ctx context.Context, snapshot *Snapshot) error {
+	labels, err := s.queries.GetLabelsByBoard(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get board labels: %w", err)
+	}
+	snapshot.Labels = labels
+	if snapshot.Labels == nil {
+		snapshot.Labels = []db.Label{}
+	}
+
+	cardLabels, err := s.queries.GetCardLabelsByBoard(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get card labels: %w", err)
+	}
+
+	labelsByCard := make(map[int32][]db.Label)
+	for _, row := range cardLabels {
+		labelsByCard[row.CardID] = append(labelsByCard[row.CardID], row.Label)
+	}
+	snapshot.eachCard(func(card *SnapshotCard) {
+		card.Labels = labelsByCard[card.ID]
+		if card.Labels == nil {
+			card.Labels = []db.Label{}
+		}
+	})
+
+	return nil
+}
+
+// includeAssignees adds the assignees of each card to the snapshot
+func (s *Service) includeAssignees(ctx context.Context, snapshot *Snapshot) error {
+	assignees, err := s.queries.GetCardAssigneesByBoard(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get card assignees: %w", err)
+	}
+
+	assigneesByCard := make(map[int32][]db.GetCardAssigneesRow)
+	for _, row := range assignees {
+		assigneesByCard[row.CardID] = append(assigneesByCard[row.CardID], db.GetCardAssigneesRow(row))
+	}
+	snapshot.eachCard(func(card *SnapshotCard) {
+		card.Assignees = assigneesByCard[card.ID]
+		if card.Assignees == nil {
+			card.Assignees = []db.GetCardAssigneesRow{}
+		}
+	})
+
+	return nil
+}
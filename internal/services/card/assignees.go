@@ -0,0 +1,126 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrAssigneeNoAccess is returned when assigning a user who cannot see the card's board
+var ErrAssigneeNoAccess = errs.New(errs.Validation, "assignee_no_access", "user does not have access to the card's board")
+
+// AssignedBoard is a board with the lists holding cards assigned to a user
+type AssignedBoard struct {
+	ID    int32
+	Name  string
+	Lists []AssignedList
+}
+
+// AssignedList is a list with the cards in it assigned to a user, in order
+type AssignedList struct {
+	ID    int32
+	Name  string
+	Cards []db.Card
+}
+
+// GetAssignees gets the users assigned to a card
+func (s *Service) GetAssignees(ctx context.Context, userID, cardID int32) ([]db.GetCardAssigneesRow, error) {
+	if _, err := s.access.Card(ctx, userID, cardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	return s.cardAssignees(ctx, cardID)
+}
+
+// Assign assigns a user with access to the card's board to the card.
+// Assigning a user who is already assigned does nothing.
+// It returns the card's assignees.
+func (s *Service) Assign(ctx context.Context, userID, cardID, assigneeID int32) ([]db.GetCardAssigneesRow, error) {
+	boardID, err := s.access.Card(ctx, userID, cardID, access.EditCards)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assignees only need to be able to see the board. Unknown users have no role on it.
+	if _, err := s.access.Board(ctx, assigneeID, boardID, access.ViewBoard); err != nil {
+		if errors.Is(err, access.ErrNotFound) || errors.Is(err, access.ErrForbidden) {
+			return nil, ErrAssigneeNoAccess
+		}
+		return nil, err
+	}
+
+	err = s.queries.AssignCard(ctx, db.AssignCardParams{
+		CardID:     cardID,
+		UserID:     assigneeID,
+		AssignedBy: pgtype.Int4{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign card: %w", err)
+	}
+
+	return s.cardAssignees(ctx, cardID)
+}
+
+// Unassign removes a user from the card's assignees.
+// It returns the card's assignees.
+func (s *Service) Unassign(ctx context.Context, userID, cardID, assigneeID int32) ([]db.GetCardAssigneesRow, error) {
+	if _, err := s.access.Card(ctx, userID, cardID, access.EditCards); err != nil {
+		return nil, err
+	}
+
+	err := s.queries.UnassignCard(ctx, db.UnassignCardParams{
+		CardID: cardID,
+		UserID: assigneeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unassign card: %w", err)
+	}
+
+	return s.cardAssignees(ctx, cardID)
+}
+
+// GetAssignedCards gets the cards assigned to the user across all boards, grouped by board and list.
+// Archived and deleted cards are left out.
+func (s *Service) GetAssignedCards(ctx context.Context, userID int32) ([]AssignedBoard, error) {
+	rows, err := s.queries.GetAssignedCards(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned cards: %w", err)
+	}
+
+	// Rows come sorted by board, then list, so groups are contiguous
+	boards := []AssignedBoard{}
+	for _, row := range rows {
+		if len(boards) == 0 || boards[len(boards)-1].ID != row.BoardID {
+			boards = append(boards, AssignedBoard{ID: row.BoardID, Name: row.BoardName})
+		}
+		board := &boards[len(boards)-1]
+
+		if len(board.Lists) == 0 || board.Lists[len(board.Lists)-1].ID != row.Card.ListID {
+			board.Lists = append(board.Lists, AssignedList{ID: row.Card.ListID, Name: row.ListName})
+		}
+		list := &board.Lists[len(board.Lists)-1]
+
+		list.Cards = append(list.Cards, row.Card)
+	}
+
+	return boards, nil
+}
+
+func (s *Service) cardAssignees(ctx context.Context, cardID int32) ([]db.GetCardAssigneesRow, error) {
+	assignees, err := s.queries.GetCardAssignees(ctx, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card assignees: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if assignees == nil {
+		return []db.GetCardAssigneesRow{}, nil
+	}
+
+	return assignees, nil
+}
@@ -5,106 +5,282 @@ import (
 	"fmt"
 
 	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var (
+	// ErrEmptyTitle is returned when the card title is empty
+	ErrEmptyTitle = errs.Invalid("title", "card title cannot be empty")
+	// ErrInvalidAnchor is returned when a card is placed next to a card that is not in the target list
+	ErrInvalidAnchor = errs.New(errs.Validation, "invalid_anchor", "after_id and before_id must be other cards in the target list, in that order")
+	// ErrCardMoved is returned when someone else moved the card to another list while it was being moved
+	ErrCardMoved = errs.New(errs.Conflict, "card_moved", "card was moved by someone else, please try again")
+	// ErrNotArchived is returned when deleting a card that has not been archived
+	ErrNotArchived = errs.New(errs.Conflict, "not_archived", "card must be archived before it can be deleted")
+)
+
+// LabeledCard is a card with the labels attached to it
+type LabeledCard struct {
+	db.Card
+	Labels []db.Label
+}
+
 // Service handles card-related business logic
 type Service struct {
 	queries *db.Queries
+	access  *access.Checker
 }
 
 // New creates a new card service
 func New(queries *db.Queries) *Service {
 	return &Service{
 		queries: queries,
+		access:  access.New(queries),
 	}
 }
 
-// CreateCard creates a new card in a list
-func (s *Service) CreateCard(ctx context.Context, title, description string, listID int32, position int32) (*db.Card, error) {
+// CreateCard creates a new card in a list.
+// It is placed right after the card afterID and before the card beforeID; either may be 0,
+// and with neither the card goes last.
+func (s *Service) CreateCard(ctx context.Context, userID int32, title, description string, listID, afterID, beforeID int32) (*db.Card, error) {
 	// Validate input
 	if title == "" {
-		return nil, fmt.Errorf("card title cannot be empty")
+		return nil, ErrEmptyTitle
+	}
+
+	if _, err := s.access.List(ctx, userID, listID, access.EditCards); err != nil {
+		return nil, err
 	}
 
 	// Create the card
-	card, err := s.queries.CreateCard(ctx, db.CreateCardParams{
-		Title:       title,
-		Description: pgtype.Text{String: description, Valid: true},
-		ListID:      listID,
-		Position:    position,
+	var card db.Card
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := lockLists(ctx, q, listID); err != nil {
+			return err
+		}
+
+		key, err := cardRank(ctx, q, listID, 0, afterID, beforeID)
+		if err != nil {
+			return err
+		}
+
+		card, err = q.CreateCard(ctx, db.CreateCardParams{
+			Title:       title,
+			Description: pgtype.Text{String: description, Valid: true},
+			ListID:      listID,
+			Rank:        key,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create card: %w", err)
+		return nil, txError("failed to create card", err)
 	}
 
 	return &card, nil
 }
 
-// GetListCards gets all cards for a specific list
-func (s *Service) GetListCards(ctx context.Context, listID int32) ([]db.Card, error) {
-	cards, err := s.queries.GetCardsByList(ctx, listID)
+// GetListCards gets all cards for a specific list, with their labels.
+// Archived cards are listed only when archived is true, and then only those.
+// When labelIDs is not empty only cards with any of those labels are listed.
+func (s *Service) GetListCards(ctx context.Context, userID, listID int32, archived bool, labelIDs []int32) ([]LabeledCard, error) {
+	if _, err := s.access.List(ctx, userID, listID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	cards, err := s.queries.GetCardsByList(ctx, db.GetCardsByListParams{
+		ListID:   listID,
+		Archived: archived,
+		LabelIds: labelIDs,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get list cards: %w", err)
 	}
 
-	// Ensure we return an empty slice instead of nil
-	if cards == nil {
-		return []db.Card{}, nil
+	cardLabels, err := s.queries.GetCardLabelsByList(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card labels: %w", err)
 	}
 
-	return cards, nil
+	labelsByCard := make(map[int32][]db.Label)
+	for _, row := range cardLabels {
+		labelsByCard[row.CardID] = append(labelsByCard[row.CardID], row.Label)
+	}
+
+	// Ensure we return empty slices instead of nil
+	labeled := make([]LabeledCard, len(cards))
+	for i, card := range cards {
+		labeled[i] = LabeledCard{Card: card, Labels: labelsByCard[card.ID]}
+		if labeled[i].Labels == nil {
+			labeled[i].Labels = []db.Label{}
+		}
+	}
+
+	return labeled, nil
 }
 
 // GetCardByID gets a single card by ID
-func (s *Service) GetCardByID(ctx context.Context, cardID int32) (*db.Card, error) {
+func (s *Service) GetCardByID(ctx context.Context, userID, cardID int32) (*db.Card, error) {
+	if _, err := s.access.Card(ctx, userID, cardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
 	card, err := s.queries.GetCardByID(ctx, cardID)
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to get card: %w", err)
 	}
 
 	return &card, nil
 }
 
-// UpdateCard updates a card's title and description
-func (s *Service) UpdateCard(ctx context.Context, cardID int32, title, description string) (*db.Card, error) {
+// UpdateCard updates a card's title and description, and the dates and completion set in schedule
+func (s *Service) UpdateCard(ctx context.Context, userID, cardID int32, title, description string, schedule Schedule) (*db.Card, error) {
 	// Validate input
 	if title == "" {
-		return nil, fmt.Errorf("card title cannot be empty")
+		return nil, ErrEmptyTitle
+	}
+
+	if _, err := s.access.Card(ctx, userID, cardID, access.EditCards); err != nil {
+		return nil, err
 	}
 
 	card, err := s.queries.UpdateCard(ctx, db.UpdateCardParams{
-		Title:       title,
-		Description: pgtype.Text{String: description, Valid: true},
-		ID:          cardID,
+		Title:        title,
+		Description:  pgtype.Text{String: description, Valid: true},
+		SetStartAt:   schedule.StartAt != nil,
+		StartAt:      timestamp(schedule.StartAt),
+		SetDueAt:     schedule.DueAt != nil,
+		DueAt:        timestamp(schedule.DueAt),
+		SetCompleted: schedule.Completed != nil,
+		Completed:    schedule.Completed != nil && *schedule.Completed,
+		ID:           cardID,
 	})
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		if errs.IsCheckViolation(err, "card_start_before_due") {
+			return nil, ErrStartAfterDue
+		}
 		return nil, fmt.Errorf("failed to update card: %w", err)
 	}
 
 	return &card, nil
 }
 
-// MoveCard moves a card to a different list and/or position
-func (s *Service) MoveCard(ctx context.Context, cardID, listID, position int32) (*db.Card, error) {
-	card, err := s.queries.MoveCard(ctx, db.MoveCardParams{
-		ListID:   listID,
-		Position: position,
+// MoveCard moves a card to a list, right after the card afterID and before the card beforeID.
+// Either anchor may be 0, and with neither the card goes last. Only the moved card is written.
+func (s *Service) MoveCard(ctx context.Context, userID, cardID, listID, afterID, beforeID int32) (*db.Card, error) {
+	// The user needs access to both the card and the list it is moved to
+	if _, err := s.access.Card(ctx, userID, cardID, access.EditCards); err != nil {
+		return nil, err
+	}
+	boardID, err := s.access.List(ctx, userID, listID, access.EditCards)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.queries.GetCardByID(ctx, cardID)
+	if err != nil {
+		return nil, txError("failed to get card", err)
+	}
+
+	var card db.Card
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		// Both lists are locked, so the card cannot leave its list or have its rank rebalanced meanwhile
+		if err := lockLists(ctx, q, source.ListID, listID); err != nil {
+			return err
+		}
+
+		// The card may have moved, possibly to a board the user cannot see, before its list was locked
+		current, err := q.GetCardForUpdate(ctx, cardID)
+		if err != nil {
+			return err
+		}
+		if current.ListID != source.ListID {
+			return ErrCardMoved
+		}
+
+		key, err := cardRank(ctx, q, listID, cardID, afterID, beforeID)
+		if err != nil {
+			return err
+		}
+
+		card, err = q.MoveCard(ctx, db.MoveCardParams{
+			ListID: listID,
+			Rank:   key,
+			ID:     cardID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// A card moved to another board loses the labels of its old board,
+		// and the assignees who cannot see the new one
+		if err := q.DetachForeignLabels(ctx, listID); err != nil {
+			return err
+		}
+		return q.UnassignUsersWithoutAccess(ctx, []int32{boardID})
+	})
+	if err != nil {
+		return nil, txError("failed to move card", err)
+	}
+
+	return &card, nil
+}
+
+// SetArchived archives a card, or restores it when archived is false.
+// Archiving an archived card keeps its original archive time.
+func (s *Service) SetArchived(ctx context.Context, userID, cardID int32, archived bool) (*db.Card, error) {
+	if _, err := s.access.Card(ctx, userID, cardID, access.DeleteCards); err != nil {
+		return nil, err
+	}
+
+	card, err := s.queries.SetCardArchived(ctx, db.SetCardArchivedParams{
+		Archived: archived,
 		ID:       cardID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to move card: %w", err)
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to archive card: %w", err)
 	}
 
 	return &card, nil
 }
 
-// DeleteCard deletes a card
-func (s *Service) DeleteCard(ctx context.Context, cardID int32) error {
-	err := s.queries.DeleteCard(ctx, cardID)
+// DeleteCard moves an archived card to the user's trash
+func (s *Service) DeleteCard(ctx context.Context, userID, cardID int32) error {
+	if _, err := s.access.Card(ctx, userID, cardID, access.DeleteCards); err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteCard(ctx, db.DeleteCardParams{
+		DeletedBy: pgtype.Int4{Int32: userID, Valid: true},
+		ID:        cardID,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete card: %w", err)
 	}
+	if rows == 0 {
+		return ErrNotArchived
+	}
 
 	return nil
 }
+
+// txError passes domain errors through and wraps anything else
+func txError(message string, err error) error {
+	if errs.IsNoRows(err) {
+		return access.ErrNotFound
+	}
+	if errs.KindOf(err) != errs.Internal {
+		return err
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
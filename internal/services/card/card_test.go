@@ -0,0 +1,318 @@
+package card_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/services/board"
+	"github.com/anubhav047/goboard/internal/services/card"
+	"github.com/anubhav047/goboard/internal/services/list"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fixture is a user owning one board with a few empty lists
+type fixture struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	cards   *card.Service
+	userID  int32
+	lists   []int32
+}
+
+func setup(t *testing.T, lists int) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	pool := dbtest.New(t)
+	queries := db.New(pool)
+
+	user, err := queries.CreateUser(ctx, db.CreateUserParams{Name: "Test", Email: "test@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := board.New(queries).CreateBoard(ctx, "Board", "", user.ID, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := fixture{pool: pool, queries: queries, cards: card.New(queries), userID: user.ID}
+	for i := 0; i < lists; i++ {
+		l, err := list.New(queries).CreateList(ctx, user.ID, fmt.Sprintf("List %d", i), b.ID, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.lists = append(f.lists, l.ID)
+	}
+
+	return f
+}
+
+// createCards adds n cards to the end of a list, one after another
+func (f fixture) createCards(t *testing.T, listID int32, n int) []int32 {
+	t.Helper()
+
+	ids := make([]int32, n)
+	for i := range ids {
+		c, err := f.cards.CreateCard(context.Background(), f.userID, fmt.Sprintf("Card %d", i), "", listID, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// ranked returns the cards of a list in rank order, failing if two share a rank
+func (f fixture) ranked(t *testing.T, listID int32) []db.Card {
+	t.Helper()
+	ctx := context.Background()
+
+	ids, err := f.queries.GetRankedCardIDs(ctx, listID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cards := make([]db.Card, len(ids))
+	for i, id := range ids {
+		if cards[i], err = f.queries.GetCardByID(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && cards[i].Rank <= cards[i-1].Rank {
+			t.Errorf("list %d: card %d rank %q does not sort after card %d rank %q",
+				listID, cards[i].ID, cards[i].Rank, cards[i-1].ID, cards[i-1].Rank)
+		}
+	}
+	return cards
+}
+
+// parallel runs fn n times at once and returns the errors. Postgres aborts one side
+// of a deadlock, so deadlocks show up as errors; the timeout catches anything that hangs.
+func parallel(t *testing.T, n int, fn func(ctx context.Context, i int) error) []error {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	start := make(chan struct{})
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(ctx, i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func TestCreateCardConcurrently(t *testing.T) {
+	f := setup(t, 1)
+	listID := f.lists[0]
+	seed := f.createCards(t, listID, 2)
+
+	// Half the cards go last, half right after the first seed card
+	const n = 40
+	created := make([]int32, n)
+	errs := parallel(t, n, func(ctx context.Context, i int) error {
+		var afterID int32
+		if i%2 == 1 {
+			afterID = seed[0]
+		}
+		c, err := f.cards.CreateCard(ctx, f.userID, fmt.Sprintf("Concurrent %d", i), "", listID, afterID, 0)
+		if err == nil {
+			created[i] = c.ID
+		}
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("CreateCard %d: %v", i, err)
+		}
+	}
+
+	cards := f.ranked(t, listID)
+	if len(cards) != n+len(seed) {
+		t.Fatalf("list has %d cards, want %d", len(cards), n+len(seed))
+	}
+
+	position := make(map[int32]int, len(cards))
+	for i, c := range cards {
+		position[c.ID] = i
+	}
+	for i, id := range created {
+		if i%2 == 1 && (position[id] <= position[seed[0]] || position[id] >= position[seed[1]]) {
+			t.Errorf("card %d was placed at %d, not between the seed cards at %d and %d",
+				id, position[id], position[seed[0]], position[seed[1]])
+		}
+		if i%2 == 0 && position[id] <= position[seed[1]] {
+			t.Errorf("card %d was placed at %d, not after the last seed card at %d", id, position[id], position[seed[1]])
+		}
+	}
+}
+
+func TestMoveCardConcurrently(t *testing.T) {
+	f := setup(t, 3)
+	const perList = 10
+	cards := make([][]int32, len(f.lists))
+	for i, listID := range f.lists {
+		cards[i] = f.createCards(t, listID, perList)
+	}
+
+	// Every card moves one list along while new cards are added to every list, so each
+	// list is at once a source and a destination. Moves to the same list reorder it.
+	type move struct {
+		cardID, listID int32
+	}
+	var moves []move
+	for i := range f.lists {
+		for j, cardID := range cards[i] {
+			target := f.lists[(i+1)%len(f.lists)]
+			if j%5 == 0 {
+				target = f.lists[i]
+			}
+			moves = append(moves, move{cardID, target})
+		}
+	}
+	const creates = 15
+
+	errs := parallel(t, len(moves)+creates, func(ctx context.Context, i int) error {
+		if i >= len(moves) {
+			listID := f.lists[i%len(f.lists)]
+			_, err := f.cards.CreateCard(ctx, f.userID, fmt.Sprintf("New %d", i), "", listID, 0, 0)
+			return err
+		}
+		_, err := f.cards.MoveCard(ctx, f.userID, moves[i].cardID, moves[i].listID, 0, 0)
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("operation %d: %v", i, err)
+		}
+	}
+
+	where := make(map[int32]int32)
+	total := 0
+	for _, listID := range f.lists {
+		for _, c := range f.ranked(t, listID) {
+			where[c.ID] = listID
+			total++
+		}
+	}
+	if want := len(f.lists)*perList + creates; total != want {
+		t.Errorf("lists hold %d cards, want %d", total, want)
+	}
+	for _, m := range moves {
+		if where[m.cardID] != m.listID {
+			t.Errorf("card %d is in list %d, want %d", m.cardID, where[m.cardID], m.listID)
+		}
+	}
+}
+
+func TestMoveCardAnchoredConcurrently(t *testing.T) {
+	f := setup(t, 2)
+	source := f.createCards(t, f.lists[0], 20)
+	target := f.createCards(t, f.lists[1], 2)
+
+	// All cards squeeze in between the same two cards of the target list
+	errs := parallel(t, len(source), func(ctx context.Context, i int) error {
+		_, err := f.cards.MoveCard(ctx, f.userID, source[i], f.lists[1], target[0], target[1])
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("MoveCard %d: %v", i, err)
+		}
+	}
+
+	cards := f.ranked(t, f.lists[1])
+	if len(cards) != len(source)+len(target) {
+		t.Fatalf("target list has %d cards, want %d", len(cards), len(source)+len(target))
+	}
+	if cards[0].ID != target[0] || cards[len(cards)-1].ID != target[1] {
+		t.Errorf("moved cards are not between the anchors: first %d, last %d", cards[0].ID, cards[len(cards)-1].ID)
+	}
+	if left := f.ranked(t, f.lists[0]); len(left) != 0 {
+		t.Errorf("source list still has %d cards", len(left))
+	}
+}
+
+func TestMoveCardMovedMeanwhile(t *testing.T) {
+	f := setup(t, 3)
+	ctx := context.Background()
+	cardID := f.createCards(t, f.lists[0], 1)[0]
+
+	// Someone else holds the card's list and moves the card to the third list
+	tx, err := f.pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	q := f.queries.WithTx(tx)
+	if _, err := q.LockLists(ctx, []int32{f.lists[0]}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.MoveCard(ctx, db.MoveCardParams{ListID: f.lists[2], Rank: "V", ID: cardID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Meanwhile the user moves it from where they last saw it to the second list
+	result := make(chan error, 1)
+	go func() {
+		_, err := f.cards.MoveCard(ctx, f.userID, cardID, f.lists[1], 0, 0)
+		result <- err
+	}()
+	waitForLock(t, f.pool)
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, card.ErrCardMoved) {
+			t.Fatalf("MoveCard() error = %v, want %v", err, card.ErrCardMoved)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("MoveCard did not finish after the other move committed")
+	}
+
+	c, err := f.queries.GetCardByID(ctx, cardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ListID != f.lists[2] {
+		t.Errorf("card is in list %d, want it left where the other move put it (%d)", c.ListID, f.lists[2])
+	}
+}
+
+// waitForLock waits until a session is blocked locking lists
+func waitForLock(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var waiting int
+		err := pool.QueryRow(context.Background(), `
+			SELECT COUNT(*) FROM pg_stat_activity
+			WHERE datname = current_database() AND wait_event_type = 'Lock' AND query LIKE '%LockLists%'`).Scan(&waiting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if waiting > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("MoveCard never waited for the list lock")
+}
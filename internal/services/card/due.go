@@ -0,0 +1,85 @@
+package card
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MaxDueWindow is the furthest ahead GetDueSoon looks
+const MaxDueWindow = 90 * 24 * time.Hour
+
+var (
+	// ErrStartAfterDue is returned when a card would start after it is due
+	ErrStartAfterDue = errs.Invalid("start_at", "start date cannot be after the due date")
+	// ErrInvalidWindow is returned when the due soon window is not positive or too long
+	ErrInvalidWindow = errs.Invalid("within", fmt.Sprintf("within must be a positive duration of at most %s", MaxDueWindow))
+)
+
+// Schedule changes the dates and completion of a card.
+// Nil fields are left unchanged and a zero time clears the date.
+type Schedule struct {
+	StartAt   *time.Time
+	DueAt     *time.Time
+	Completed *bool
+}
+
+// DueCard is a card with a due date, and where to find it
+type DueCard struct {
+	db.Card
+	BoardID   int32
+	BoardName string
+	ListName  string
+}
+
+// GetDueSoon gets the open cards due within the given time from now, on every board the user can see
+func (s *Service) GetDueSoon(ctx context.Context, userID int32, within time.Duration) ([]DueCard, error) {
+	if within <= 0 || within > MaxDueWindow {
+		return nil, ErrInvalidWindow
+	}
+
+	now := time.Now().UTC()
+	return s.dueCards(ctx, userID, &now, now.Add(within))
+}
+
+// GetOverdue gets the open cards past their due date on every board the user can see
+func (s *Service) GetOverdue(ctx context.Context, userID int32) ([]DueCard, error) {
+	return s.dueCards(ctx, userID, nil, time.Now().UTC())
+}
+
+// dueCards gets the open cards due from after, or any time when it is nil, until before
+func (s *Service) dueCards(ctx context.Context, userID int32, after *time.Time, before time.Time) ([]DueCard, error) {
+	rows, err := s.queries.GetDueCards(ctx, db.GetDueCardsParams{
+		DueBefore: pgtype.Timestamptz{Time: before, Valid: true},
+		DueAfter:  timestamp(after),
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due cards: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	cards := make([]DueCard, len(rows))
+	for i, row := range rows {
+		cards[i] = DueCard{
+			Card:      row.Card,
+			BoardID:   row.BoardID,
+			BoardName: row.BoardName,
+			ListName:  row.ListName,
+		}
+	}
+
+	return cards, nil
+}
+
+// timestamp converts an optional time to UTC, treating nil and the zero time as null
+func timestamp(t *time.Time) pgtype.Timestamptz {
+	if t == nil || t.IsZero() {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,124 @@
+package card_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/services/card"
+)
+
+// schedule sets the due date of a card, and optionally completes it
+func (f fixture) schedule(t *testing.T, cardID int32, dueAt time.Time, completed bool) {
+	t.Helper()
+
+	_, err := f.cards.UpdateCard(context.Background(), f.userID, cardID, "Card", "", card.Schedule{
+		DueAt:     &dueAt,
+		Completed: &completed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func dueIDs(cards []card.DueCard) []int32 {
+	ids := make([]int32, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestDueCards(t *testing.T) {
+	f := setup(t, 1)
+	ctx := context.Background()
+	now := time.Now()
+
+	ids := f.createCards(t, f.lists[0], 8)
+	// ids[3] never gets a due date
+	overdue, soon, later, completed, archived, deleted, outOfZone := ids[0], ids[1], ids[2], ids[4], ids[5], ids[6], ids[7]
+
+	f.schedule(t, overdue, now.Add(-time.Hour), false)
+	f.schedule(t, soon, now.Add(time.Hour), false)
+	f.schedule(t, later, now.Add(48*time.Hour), false)
+	f.schedule(t, completed, now.Add(-time.Hour), true)
+	f.schedule(t, archived, now.Add(-time.Hour), false)
+	f.schedule(t, deleted, now.Add(-time.Hour), false)
+	// Due two hours from now, written in a time zone far from UTC
+	f.schedule(t, outOfZone, now.Add(2*time.Hour).In(time.FixedZone("UTC+14", 14*60*60)), false)
+
+	// Only archived cards can be deleted
+	for _, id := range []int32{archived, deleted} {
+		if _, err := f.cards.SetArchived(ctx, f.userID, id, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.cards.DeleteCard(ctx, f.userID, deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() ([]card.DueCard, error)
+		want []int32
+	}{
+		{"overdue", func() ([]card.DueCard, error) { return f.cards.GetOverdue(ctx, f.userID) }, []int32{overdue}},
+		{"due within a day", func() ([]card.DueCard, error) { return f.cards.GetDueSoon(ctx, f.userID, 24*time.Hour) }, []int32{soon, outOfZone}},
+		{"due within three days", func() ([]card.DueCard, error) { return f.cards.GetDueSoon(ctx, f.userID, 72*time.Hour) }, []int32{soon, outOfZone, later}},
+		{"due within a minute", func() ([]card.DueCard, error) { return f.cards.GetDueSoon(ctx, f.userID, time.Minute) }, []int32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := tt.get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dueIDs(cards); !slices.Equal(got, tt.want) {
+				t.Errorf("got cards %v, want %v", got, tt.want)
+			}
+			for _, c := range cards {
+				if c.ListName != "List 0" || c.BoardName != "Board" || c.BoardID == 0 {
+					t.Errorf("card %d is on %q/%q of board %d, want Board/List 0", c.ID, c.BoardName, c.ListName, c.BoardID)
+				}
+				if loc := c.DueAt.Time.Location(); loc != time.UTC {
+					t.Errorf("card %d due date is in %s, want UTC", c.ID, loc)
+				}
+			}
+		})
+	}
+}
+
+// TestDueCardsOtherUsers checks that cards on boards a user cannot see are left out
+func TestDueCardsOtherUsers(t *testing.T) {
+	f := setup(t, 1)
+	ctx := context.Background()
+
+	ids := f.createCards(t, f.lists[0], 1)
+	f.schedule(t, ids[0], time.Now().Add(-time.Hour), false)
+
+	other, err := f.queries.CreateUser(ctx, db.CreateUserParams{Name: "Other", Email: "other@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := f.cards.GetOverdue(ctx, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 0 {
+		t.Errorf("other user sees overdue cards %v", dueIDs(cards))
+	}
+}
+
+func TestDueSoonWindow(t *testing.T) {
+	f := setup(t, 0)
+
+	for _, within := range []time.Duration{0, -time.Hour, card.MaxDueWindow + time.Hour} {
+		if _, err := f.cards.GetDueSoon(context.Background(), f.userID, within); !errors.Is(err, card.ErrInvalidWindow) {
+			t.Errorf("GetDueSoon(%s) = %v, want ErrInvalidWindow", within, err)
+		}
+	}
+}
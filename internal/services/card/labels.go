@@ -0,0 +1,85 @@
+package card
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+// ErrLabelNotOnBoard is returned when attaching a label from another board
+var ErrLabelNotOnBoard = errs.New(errs.Validation, "label_not_on_board", "label does not belong to the card's board")
+
+// AttachLabel adds a label of the card's board to the card.
+// Attaching a label the card already has does nothing.
+// It returns the card's labels.
+func (s *Service) AttachLabel(ctx context.Context, userID, cardID, labelID int32) ([]db.Label, error) {
+	if err := s.checkCardLabel(ctx, userID, cardID, labelID); err != nil {
+		return nil, err
+	}
+
+	err := s.queries.AttachCardLabel(ctx, db.AttachCardLabelParams{
+		CardID:  cardID,
+		LabelID: labelID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach label: %w", err)
+	}
+
+	return s.cardLabels(ctx, cardID)
+}
+
+// DetachLabel removes a label from the card.
+// It returns the card's labels.
+func (s *Service) DetachLabel(ctx context.Context, userID, cardID, labelID int32) ([]db.Label, error) {
+	if err := s.checkCardLabel(ctx, userID, cardID, labelID); err != nil {
+		return nil, err
+	}
+
+	err := s.queries.DetachCardLabel(ctx, db.DetachCardLabelParams{
+		CardID:  cardID,
+		LabelID: labelID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detach label: %w", err)
+	}
+
+	return s.cardLabels(ctx, cardID)
+}
+
+// checkCardLabel verifies that the user may edit the card and that the label belongs to its board
+func (s *Service) checkCardLabel(ctx context.Context, userID, cardID, labelID int32) error {
+	boardID, err := s.access.Card(ctx, userID, cardID, access.EditCards)
+	if err != nil {
+		return err
+	}
+
+	label, err := s.queries.GetLabelByID(ctx, labelID)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrLabelNotOnBoard
+		}
+		return fmt.Errorf("failed to get label: %w", err)
+	}
+	if label.BoardID != boardID {
+		return ErrLabelNotOnBoard
+	}
+
+	return nil
+}
+
+func (s *Service) cardLabels(ctx context.Context, cardID int32) ([]db.Label, error) {
+	labels, err := s.queries.GetCardLabels(ctx, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card labels: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if labels == nil {
+		return []db.Label{}, nil
+	}
+
+	return labels, nil
+}
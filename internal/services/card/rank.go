@@ -0,0 +1,86 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/rank"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+// cardRanks is the rank.Store of cards, which are ordered within their list
+type cardRanks struct {
+	q *db.Queries
+}
+
+func (r cardRanks) Item(ctx context.Context, id int32) (int32, string, error) {
+	card, err := r.q.GetCardByID(ctx, id)
+	return card.ListID, card.Rank, err
+}
+
+func (r cardRanks) Next(ctx context.Context, listID int32, key string, exclude int32) (string, error) {
+	return r.q.GetNextCardRank(ctx, db.GetNextCardRankParams{ListID: listID, Rank: key, ExcludeID: exclude})
+}
+
+func (r cardRanks) Prev(ctx context.Context, listID int32, key string, exclude int32) (string, error) {
+	return r.q.GetPrevCardRank(ctx, db.GetPrevCardRankParams{ListID: listID, Rank: key, ExcludeID: exclude})
+}
+
+func (r cardRanks) Last(ctx context.Context, listID, exclude int32) (string, error) {
+	return r.q.GetLastCardRank(ctx, db.GetLastCardRankParams{ListID: listID, ExcludeID: exclude})
+}
+
+func (r cardRanks) Lock(ctx context.Context, listIDs []int32) ([]int32, error) {
+	return r.q.LockLists(ctx, listIDs)
+}
+
+// Ranked includes archived cards, so they keep their place and can be restored to it
+func (r cardRanks) Ranked(ctx context.Context, listID int32) ([]int32, error) {
+	return r.q.GetRankedCardIDs(ctx, listID)
+}
+
+func (r cardRanks) SetRanks(ctx context.Context, ids []int32, keys []string) error {
+	return r.q.SetCardRanks(ctx, db.SetCardRanksParams{Ids: ids, Ranks: keys})
+}
+
+// cardRank returns a rank that places a card in a list right after the card afterID
+// and before the card beforeID. Either anchor may be 0; with neither the card goes last.
+// cardID is the card being placed, or 0 for a new card.
+func cardRank(ctx context.Context, q *db.Queries, listID, cardID, afterID, beforeID int32) (string, error) {
+	key, err := rank.Place(ctx, cardRanks{q}, listID, cardID, afterID, beforeID)
+	if errors.Is(err, rank.ErrInvalidAnchor) {
+		return "", ErrInvalidAnchor
+	}
+	return key, err
+}
+
+// lockLists locks lists so cards are placed in them one at a time
+func lockLists(ctx context.Context, q *db.Queries, listIDs ...int32) error {
+	err := rank.Lock(ctx, cardRanks{q}, listIDs...)
+	if errors.Is(err, rank.ErrGroupNotFound) {
+		return access.ErrNotFound
+	}
+	return err
+}
+
+// RebalanceRanks gives the cards of every list whose ranks have grown longer than maxLength
+// new, evenly spaced ranks. It returns the number of lists that were rebalanced.
+func (s *Service) RebalanceRanks(ctx context.Context, maxLength int32) (int, error) {
+	listIDs, err := s.queries.GetListsWithLongCardRanks(ctx, maxLength)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get lists to rebalance: %w", err)
+	}
+
+	rebalanced, err := rank.Rebalance(ctx, listIDs, func(fn func(rank.Store) error) error {
+		return s.queries.ExecTx(ctx, func(q *db.Queries) error {
+			return fn(cardRanks{q})
+		})
+	})
+	if err != nil {
+		return rebalanced, fmt.Errorf("failed to rebalance card ranks: %w", err)
+	}
+
+	return rebalanced, nil
+}
@@ -0,0 +1,90 @@
+package card
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Reminder offsets must fall within these bounds
+const (
+	MinReminderOffset = time.Minute
+	MaxReminderOffset = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidReminderOffset is returned when a reminder offset is not a duration within bounds
+	ErrInvalidReminderOffset = errs.Invalid("before", fmt.Sprintf("before must be a duration like 1h or 24h, between %s and %s", MinReminderOffset, MaxReminderOffset))
+	// ErrDuplicateReminder is returned when the card already has a reminder with the offset
+	ErrDuplicateReminder = errs.New(errs.Conflict, "duplicate_reminder", "card already has a reminder at this time")
+)
+
+// GetReminders gets the reminders of a card, earliest first
+func (s *Service) GetReminders(ctx context.Context, userID, cardID int32) ([]db.CardReminder, error) {
+	if _, err := s.access.Card(ctx, userID, cardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.queries.GetCardReminders(ctx, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card reminders: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if reminders == nil {
+		return []db.CardReminder{}, nil
+	}
+
+	return reminders, nil
+}
+
+// AddReminder adds a reminder that fires the given time before the card is due.
+// Assignees are notified once per due date; moving the due date re-arms the reminder.
+func (s *Service) AddReminder(ctx context.Context, userID, cardID int32, before time.Duration) (*db.CardReminder, error) {
+	if before < MinReminderOffset || before > MaxReminderOffset {
+		return nil, ErrInvalidReminderOffset
+	}
+
+	if _, err := s.access.Card(ctx, userID, cardID, access.EditCards); err != nil {
+		return nil, err
+	}
+
+	reminder, err := s.queries.CreateCardReminder(ctx, db.CreateCardReminderParams{
+		CardID:        cardID,
+		OffsetSeconds: int32(before / time.Second),
+		CreatedBy:     pgtype.Int4{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		if errs.IsUniqueViolation(err) {
+			return nil, ErrDuplicateReminder
+		}
+		return nil, fmt.Errorf("failed to create card reminder: %w", err)
+	}
+
+	return &reminder, nil
+}
+
+// DeleteReminder removes a reminder from a card
+func (s *Service) DeleteReminder(ctx context.Context, userID, cardID, reminderID int32) error {
+	if _, err := s.access.Card(ctx, userID, cardID, access.EditCards); err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteCardReminder(ctx, db.DeleteCardReminderParams{
+		ID:     reminderID,
+		CardID: cardID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete card reminder: %w", err)
+	}
+	if rows == 0 {
+		return access.ErrNotFound
+	}
+
+	return nil
+}
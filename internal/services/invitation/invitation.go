@@ -0,0 +1,310 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// invitationTTL is how long an invitation can be accepted for
+const invitationTTL = 7 * 24 * time.Hour
+
+// Invitation statuses
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+	StatusRevoked  = "revoked"
+)
+
+var (
+	ErrInvalidEmail       = errs.Invalid("email", "a valid email address is required")
+	ErrInvalidRole        = errs.Invalid("role", "role must be one of admin, editor or viewer")
+	ErrAlreadyMember      = errs.New(errs.Conflict, "already_member", "user is already a member of this board")
+	ErrInvitationNotFound = errs.New(errs.NotFound, "invitation_not_found", "invitation not found")
+	ErrInvalidToken       = errs.New(errs.Validation, "invalid_invitation_token", "invalid invitation token")
+	ErrInvitationExpired  = errs.New(errs.Gone, "invitation_expired", "invitation has expired")
+	ErrInvitationClosed   = errs.New(errs.Conflict, "invitation_closed", "invitation is no longer pending")
+	ErrWrongRecipient     = errs.New(errs.Forbidden, "wrong_recipient", "invitation was sent to a different email address")
+	ErrEmailNotVerified   = errs.New(errs.Forbidden, "email_not_verified", "verify your email address or use the link from the invitation email")
+)
+
+// Service handles board invitation business logic
+type Service struct {
+	queries *db.Queries
+	access  *access.Checker
+	signer  *token.Signer
+	mailer  mail.Mailer
+	baseURL string
+}
+
+// New creates a new invitation service.
+// baseURL is the address of the frontend used to build invitation links.
+func New(queries *db.Queries, signer *token.Signer, mailer mail.Mailer, baseURL string) *Service {
+	return &Service{
+		queries: queries,
+		access:  access.New(queries),
+		signer:  signer,
+		mailer:  mailer,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Invite creates an invitation to a board and emails it to the given address.
+// Any earlier pending invitation for the same address is revoked.
+func (s *Service) Invite(ctx context.Context, userID, boardID int32, email, role string) (*db.BoardInvitation, error) {
+	// Validate input
+	addr, err := netmail.ParseAddress(email)
+	if err != nil {
+		return nil, ErrInvalidEmail
+	}
+	email = addr.Address
+
+	memberRole, err := access.ParseRole(role)
+	if err != nil || memberRole == access.RoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return nil, err
+	}
+
+	board, err := s.queries.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+
+	// Link the invitation straight away if the email belongs to a verified account.
+	// Unverified accounts only get it attached once they verify the address.
+	var inviteeID pgtype.Int4
+	invitee, err := s.queries.GetUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		_, err := s.queries.GetBoardMember(ctx, db.GetBoardMemberParams{BoardID: boardID, UserID: invitee.ID})
+		if err == nil {
+			return nil, ErrAlreadyMember
+		}
+		if !errs.IsNoRows(err) {
+			return nil, fmt.Errorf("failed to get board member: %w", err)
+		}
+		if invitee.EmailVerifiedAt.Valid {
+			inviteeID = pgtype.Int4{Int32: invitee.ID, Valid: true}
+		}
+	case !errs.IsNoRows(err):
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	plain, hash, err := s.signer.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	// The email is sent inside the transaction so a failed delivery leaves no dangling invitation
+	var invitation db.BoardInvitation
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		err := q.RevokePendingInvitations(ctx, db.RevokePendingInvitationsParams{
+			BoardID: boardID,
+			Email:   email,
+		})
+		if err != nil {
+			return err
+		}
+
+		invitation, err = q.CreateBoardInvitation(ctx, db.CreateBoardInvitationParams{
+			BoardID:   boardID,
+			Email:     email,
+			Role:      string(memberRole),
+			TokenHash: hash,
+			InvitedBy: userID,
+			InviteeID: inviteeID,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(invitationTTL), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.mailer.Send(ctx, mail.Message{
+			To:      email,
+			Subject: fmt.Sprintf("You have been invited to %s on GoBoard", board.Name),
+			Body: fmt.Sprintf("You have been invited to join the board %q as %s.\n\nOpen this link to accept or decline the invitation:\n%s/invitations?token=%s\n\nThe invitation expires in %d days.",
+				board.Name, memberRole, s.baseURL, plain, int(invitationTTL.Hours()/24)),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// GetBoardInvitations gets all invitations sent for a board
+func (s *Service) GetBoardInvitations(ctx context.Context, userID, boardID int32) ([]db.GetBoardInvitationsRow, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.queries.GetBoardInvitations(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board invitations: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if invitations == nil {
+		return []db.GetBoardInvitationsRow{}, nil
+	}
+
+	return invitations, nil
+}
+
+// Revoke cancels a pending invitation
+func (s *Service) Revoke(ctx context.Context, userID, boardID, invitationID int32) error {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ManageBoard); err != nil {
+		return err
+	}
+
+	_, err := s.queries.RevokeBoardInvitation(ctx, db.RevokeBoardInvitationParams{
+		ID:      invitationID,
+		BoardID: boardID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrInvitationNotFound
+		}
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserInvitations gets the pending invitations attached to a user
+func (s *Service) GetUserInvitations(ctx context.Context, userID int32) ([]db.GetPendingInvitationsForUserRow, error) {
+	invitations, err := s.queries.GetPendingInvitationsForUser(ctx, pgtype.Int4{Int32: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if invitations == nil {
+		return []db.GetPendingInvitationsForUserRow{}, nil
+	}
+
+	return invitations, nil
+}
+
+// Accept accepts an invitation and adds the user to the board.
+// The invitation is looked up by token, or by ID when it is attached to the user
+// and their email is verified.
+func (s *Service) Accept(ctx context.Context, user db.User, tok string, invitationID int32) (*db.BoardInvitation, error) {
+	invitation, err := s.resolve(ctx, user, tok, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		invitation, err = q.RespondToInvitation(ctx, db.RespondToInvitationParams{
+			Status:    StatusAccepted,
+			InviteeID: pgtype.Int4{Int32: user.ID, Valid: true},
+			ID:        invitation.ID,
+		})
+		if err != nil {
+			if errs.IsNoRows(err) {
+				return ErrInvitationClosed
+			}
+			return err
+		}
+
+		return q.EnsureBoardMember(ctx, db.EnsureBoardMemberParams{
+			BoardID: invitation.BoardID,
+			UserID:  user.ID,
+			Role:    invitation.Role,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvitationClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// Decline declines an invitation
+func (s *Service) Decline(ctx context.Context, user db.User, tok string, invitationID int32) (*db.BoardInvitation, error) {
+	invitation, err := s.resolve(ctx, user, tok, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err = s.queries.RespondToInvitation(ctx, db.RespondToInvitationParams{
+		Status:    StatusDeclined,
+		InviteeID: pgtype.Int4{Int32: user.ID, Valid: true},
+		ID:        invitation.ID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, ErrInvitationClosed
+		}
+		return nil, fmt.Errorf("failed to decline invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// resolve finds a pending invitation addressed to the user.
+// Holding the token proves access to the invited mailbox; without it the user's
+// own email must be verified.
+func (s *Service) resolve(ctx context.Context, user db.User, tok string, invitationID int32) (db.BoardInvitation, error) {
+	if tok == "" && !user.EmailVerifiedAt.Valid {
+		return db.BoardInvitation{}, ErrEmailNotVerified
+	}
+
+	var invitation db.BoardInvitation
+	var err error
+	if tok != "" {
+		// Reject forged tokens before touching the database
+		if !s.signer.Verify(tok) {
+			return db.BoardInvitation{}, ErrInvalidToken
+		}
+		invitation, err = s.queries.GetInvitationByTokenHash(ctx, token.Hash(tok))
+	} else {
+		invitation, err = s.queries.GetInvitationByID(ctx, invitationID)
+		// Without a token the invitation must already be attached to the user
+		if err == nil && invitation.InviteeID.Int32 != user.ID {
+			err = pgx.ErrNoRows
+		}
+	}
+	if err != nil {
+		if errs.IsNoRows(err) {
+			if tok != "" {
+				return db.BoardInvitation{}, ErrInvalidToken
+			}
+			return db.BoardInvitation{}, ErrInvitationNotFound
+		}
+		return db.BoardInvitation{}, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	if invitation.Status != StatusPending {
+		return db.BoardInvitation{}, ErrInvitationClosed
+	}
+	if time.Now().After(invitation.ExpiresAt.Time) {
+		return db.BoardInvitation{}, ErrInvitationExpired
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) && invitation.InviteeID.Int32 != user.ID {
+		return db.BoardInvitation{}, ErrWrongRecipient
+	}
+
+	return invitation, nil
+}
@@ -5,43 +5,86 @@ import (
 	"fmt"
 
 	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrEmptyName is returned when the list name is empty
+	ErrEmptyName = errs.Invalid("name", "list name cannot be empty")
+	// ErrInvalidAnchor is returned when a list is placed next to a list that is not on the board
+	ErrInvalidAnchor = errs.New(errs.Validation, "invalid_anchor", "after_id and before_id must be other lists on the board, in that order")
+	// ErrListMoved is returned when someone else moved the list to another board while it was being moved
+	ErrListMoved = errs.New(errs.Conflict, "list_moved", "list was moved by someone else, please try again")
+	// ErrNotArchived is returned when deleting a list that has not been archived
+	ErrNotArchived = errs.New(errs.Conflict, "not_archived", "list must be archived before it can be deleted")
 )
 
 // Service handles list-related business logic
 type Service struct {
 	queries *db.Queries
+	access  *access.Checker
 }
 
 // New creates a new list service
 func New(queries *db.Queries) *Service {
 	return &Service{
 		queries: queries,
+		access:  access.New(queries),
 	}
 }
 
-// CreateList creates a new list in a board
-func (s *Service) CreateList(ctx context.Context, name string, boardID int32, position int32) (*db.List, error) {
+// CreateList creates a new list in a board.
+// It is placed right after the list afterID and before the list beforeID; either may be 0,
+// and with neither the list goes last.
+func (s *Service) CreateList(ctx context.Context, userID int32, name string, boardID, afterID, beforeID int32) (*db.List, error) {
 	// Validate input
 	if name == "" {
-		return nil, fmt.Errorf("fist name cannot be empty")
+		return nil, ErrEmptyName
+	}
+
+	if _, err := s.access.Board(ctx, userID, boardID, access.EditLists); err != nil {
+		return nil, err
 	}
 
 	// Create the list
-	list, err := s.queries.CreateList(ctx, db.CreateListParams{
-		Name:     name,
-		BoardID:  boardID,
-		Position: position,
+	var list db.List
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := lockBoards(ctx, q, boardID); err != nil {
+			return err
+		}
+
+		key, err := listRank(ctx, q, boardID, 0, afterID, beforeID)
+		if err != nil {
+			return err
+		}
+
+		list, err = q.CreateList(ctx, db.CreateListParams{
+			Name:    name,
+			BoardID: boardID,
+			Rank:    key,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create list: %w", err)
+		return nil, txError("failed to create list", err)
 	}
 
 	return &list, nil
 }
 
-// GetBoardLists gets all lists for a specified board
-func (s *Service) GetBoardLists(ctx context.Context, boardID int32) ([]db.List, error) {
-	lists, err := s.queries.GetListsByBoard(ctx, boardID)
+// GetBoardLists gets all lists for a specified board.
+// Archived lists are listed only when archived is true, and then only those.
+func (s *Service) GetBoardLists(ctx context.Context, userID, boardID int32, archived bool) ([]db.List, error) {
+	if _, err := s.access.Board(ctx, userID, boardID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
+	lists, err := s.queries.GetListsByBoard(ctx, db.GetListsByBoardParams{
+		BoardID:  boardID,
+		Archived: archived,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed tp get board lists: %w", err)
 	}
@@ -55,40 +98,159 @@ func (s *Service) GetBoardLists(ctx context.Context, boardID int32) ([]db.List,
 }
 
 // GetListByID gets a single list by ID
-func (s *Service) GetListByID(ctx context.Context, listID int32) (*db.List, error) {
+func (s *Service) GetListByID(ctx context.Context, userID, listID int32) (*db.List, error) {
+	if _, err := s.access.List(ctx, userID, listID, access.ViewBoard); err != nil {
+		return nil, err
+	}
+
 	list, err := s.queries.GetListByID(ctx, listID)
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to get list: %w", err)
 	}
 
 	return &list, nil
 }
 
-// UpdateList updates a list's name and position
-func (s *Service) UpdateList(ctx context.Context, listID int32, name string, position int32) (*db.List, error) {
+// UpdateList updates a list's name
+func (s *Service) UpdateList(ctx context.Context, userID, listID int32, name string) (*db.List, error) {
 	// Validate input
 	if name == "" {
-		return nil, fmt.Errorf("list name cannot be empty")
+		return nil, ErrEmptyName
+	}
+
+	if _, err := s.access.List(ctx, userID, listID, access.EditLists); err != nil {
+		return nil, err
 	}
 
 	list, err := s.queries.UpdateList(ctx, db.UpdateListParams{
-		Name:     name,
-		Position: position,
-		ID:       listID,
+		Name: name,
+		ID:   listID,
 	})
 	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to update list: %w", err)
 	}
 
 	return &list, nil
 }
 
-// DeleteList deletes a list
-func (s *Service) DeleteList(ctx context.Context, listID int32) error {
-	err := s.queries.DeleteList(ctx, listID)
+// MoveList moves a list, with its cards, to a board: right after the list afterID and
+// before the list beforeID. Either anchor may be 0, and with neither the list goes last.
+// A boardID of 0 keeps the list on its board. Taking a list off a board needs permission
+// to delete lists there.
+func (s *Service) MoveList(ctx context.Context, userID, listID, boardID, afterID, beforeID int32) (*db.List, error) {
+	sourceID, err := s.access.List(ctx, userID, listID, access.EditLists)
+	if err != nil {
+		return nil, err
+	}
+	if boardID == 0 {
+		boardID = sourceID
+	}
+	if boardID != sourceID {
+		if _, err := s.access.List(ctx, userID, listID, access.DeleteLists); err != nil {
+			return nil, err
+		}
+		if _, err := s.access.Board(ctx, userID, boardID, access.EditLists); err != nil {
+			return nil, err
+		}
+	}
+
+	var list db.List
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := lockBoards(ctx, q, sourceID, boardID); err != nil {
+			return err
+		}
+
+		// The list may have moved before its board was locked
+		current, err := q.GetListByID(ctx, listID)
+		if err != nil {
+			return err
+		}
+		if current.BoardID != sourceID {
+			return ErrListMoved
+		}
+
+		key, err := listRank(ctx, q, boardID, listID, afterID, beforeID)
+		if err != nil {
+			return err
+		}
+
+		list, err = q.MoveList(ctx, db.MoveListParams{
+			BoardID: boardID,
+			Rank:    key,
+			ID:      listID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Cards moved to another board lose the labels of their old board,
+		// and the assignees who cannot see the new one
+		if err := q.DetachForeignLabels(ctx, listID); err != nil {
+			return err
+		}
+		return q.UnassignUsersWithoutAccess(ctx, []int32{boardID})
+	})
+	if err != nil {
+		return nil, txError("failed to move list", err)
+	}
+
+	return &list, nil
+}
+
+// SetArchived archives a list, or restores it when archived is false.
+// Archiving an archived list keeps its original archive time.
+func (s *Service) SetArchived(ctx context.Context, userID, listID int32, archived bool) (*db.List, error) {
+	if _, err := s.access.List(ctx, userID, listID, access.DeleteLists); err != nil {
+		return nil, err
+	}
+
+	list, err := s.queries.SetListArchived(ctx, db.SetListArchivedParams{
+		Archived: archived,
+		ID:       listID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to archive list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// DeleteList moves an archived list to the user's trash
+func (s *Service) DeleteList(ctx context.Context, userID, listID int32) error {
+	if _, err := s.access.List(ctx, userID, listID, access.DeleteLists); err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteList(ctx, db.DeleteListParams{
+		DeletedBy: pgtype.Int4{Int32: userID, Valid: true},
+		ID:        listID,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete list: %w", err)
 	}
+	if rows == 0 {
+		return ErrNotArchived
+	}
 
 	return nil
 }
+
+// txError passes domain errors through and wraps anything else
+func txError(message string, err error) error {
+	if errs.IsNoRows(err) {
+		return access.ErrNotFound
+	}
+	if errs.KindOf(err) != errs.Internal {
+		return err
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
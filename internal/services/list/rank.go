@@ -0,0 +1,86 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/rank"
+	"github.com/anubhav047/goboard/internal/services/access"
+)
+
+// listRanks is the rank.Store of lists, which are ordered on their board
+type listRanks struct {
+	q *db.Queries
+}
+
+func (r listRanks) Item(ctx context.Context, id int32) (int32, string, error) {
+	list, err := r.q.GetListByID(ctx, id)
+	return list.BoardID, list.Rank, err
+}
+
+func (r listRanks) Next(ctx context.Context, boardID int32, key string, exclude int32) (string, error) {
+	return r.q.GetNextListRank(ctx, db.GetNextListRankParams{BoardID: boardID, Rank: key, ExcludeID: exclude})
+}
+
+func (r listRanks) Prev(ctx context.Context, boardID int32, key string, exclude int32) (string, error) {
+	return r.q.GetPrevListRank(ctx, db.GetPrevListRankParams{BoardID: boardID, Rank: key, ExcludeID: exclude})
+}
+
+func (r listRanks) Last(ctx context.Context, boardID, exclude int32) (string, error) {
+	return r.q.GetLastListRank(ctx, db.GetLastListRankParams{BoardID: boardID, ExcludeID: exclude})
+}
+
+func (r listRanks) Lock(ctx context.Context, boardIDs []int32) ([]int32, error) {
+	return r.q.LockBoards(ctx, boardIDs)
+}
+
+// Ranked includes archived lists, so they keep their place and can be restored to it
+func (r listRanks) Ranked(ctx context.Context, boardID int32) ([]int32, error) {
+	return r.q.GetRankedListIDs(ctx, boardID)
+}
+
+func (r listRanks) SetRanks(ctx context.Context, ids []int32, keys []string) error {
+	return r.q.SetListRanks(ctx, db.SetListRanksParams{Ids: ids, Ranks: keys})
+}
+
+// listRank returns a rank that places a list on a board right after the list afterID
+// and before the list beforeID. Either anchor may be 0; with neither the list goes last.
+// listID is the list being placed, or 0 for a new list.
+func listRank(ctx context.Context, q *db.Queries, boardID, listID, afterID, beforeID int32) (string, error) {
+	key, err := rank.Place(ctx, listRanks{q}, boardID, listID, afterID, beforeID)
+	if errors.Is(err, rank.ErrInvalidAnchor) {
+		return "", ErrInvalidAnchor
+	}
+	return key, err
+}
+
+// lockBoards locks boards so lists are placed on them one at a time
+func lockBoards(ctx context.Context, q *db.Queries, boardIDs ...int32) error {
+	err := rank.Lock(ctx, listRanks{q}, boardIDs...)
+	if errors.Is(err, rank.ErrGroupNotFound) {
+		return access.ErrNotFound
+	}
+	return err
+}
+
+// RebalanceRanks gives the lists of every board whose ranks have grown longer than maxLength
+// new, evenly spaced ranks. It returns the number of boards that were rebalanced.
+func (s *Service) RebalanceRanks(ctx context.Context, maxLength int32) (int, error) {
+	boardIDs, err := s.queries.GetBoardsWithLongListRanks(ctx, maxLength)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get boards to rebalance: %w", err)
+	}
+
+	rebalanced, err := rank.Rebalance(ctx, boardIDs, func(fn func(rank.Store) error) error {
+		return s.queries.ExecTx(ctx, func(q *db.Queries) error {
+			return fn(listRanks{q})
+		})
+	})
+	if err != nil {
+		return rebalanced, fmt.Errorf("failed to rebalance list ranks: %w", err)
+	}
+
+	return rebalanced, nil
+}
@@ -0,0 +1,258 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Security event types recorded by the guard
+const (
+	EventLoginFailed    = "login_failed"
+	EventLoginSucceeded = "login_succeeded"
+	EventLoginThrottled = "login_throttled"
+	EventAccountLocked  = "account_locked"
+	EventAddressLocked  = "address_locked"
+)
+
+// Limit controls how failures for one kind of key are punished
+type Limit struct {
+	// FreeAttempts is how many failures are allowed before delays start
+	FreeAttempts int
+	// LockoutAfter is the number of failures that locks the key out
+	LockoutAfter int
+	// LockoutDuration is how long a lockout lasts
+	LockoutDuration time.Duration
+}
+
+// Config holds the settings of the Guard
+type Config struct {
+	// Account limits failures per email address
+	Account Limit
+	// IP limits failures per client address, across all accounts
+	IP Limit
+	// BaseDelay is the first delay after the free attempts; it doubles with every further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential delay
+	MaxDelay time.Duration
+	// Window is how long a failure counts towards the streak
+	Window time.Duration
+}
+
+// DefaultConfig returns the limits used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		Account:   Limit{FreeAttempts: 3, LockoutAfter: 10, LockoutDuration: 15 * time.Minute},
+		IP:        Limit{FreeAttempts: 20, LockoutAfter: 100, LockoutDuration: time.Hour},
+		BaseDelay: time.Second,
+		MaxDelay:  5 * time.Minute,
+		Window:    time.Hour,
+	}
+}
+
+// ThrottledError is returned when a login must wait before it may be tried again
+type ThrottledError struct {
+	RetryAfter time.Duration
+	// message replaces the login message for actions limited by a Limiter
+	message string
+}
+
+func (e *ThrottledError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	return "too many failed login attempts, try again later"
+}
+
+// Attempt describes a login attempt
+type Attempt struct {
+	Email     string
+	IPAddress string
+	UserAgent string
+}
+
+// Reservation is a login attempt that Reserve let through and counted as failed.
+// Finish it with Fail, Succeed or Release once the outcome is known.
+type Reservation struct {
+	attempt Attempt
+	// failures is the streak of each key, including this attempt
+	failures []int
+}
+
+// Guard slows down and locks out repeated failed logins per account and per client address,
+// and records what happened in the security_events table.
+type Guard struct {
+	store   Store
+	queries *db.Queries
+	config  Config
+	now     func() time.Time
+}
+
+// New creates a new Guard
+func New(store Store, queries *db.Queries, config Config) *Guard {
+	// A lockout must not outlive the streak that caused it
+	config.Window = max(config.Window, config.Account.LockoutDuration, config.IP.LockoutDuration)
+
+	return &Guard{
+		store:   store,
+		queries: queries,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// Reserve lets a login attempt through unless the account or the client address must wait,
+// in which case it returns a *ThrottledError. The attempt is counted as failed before the
+// credentials are checked, so concurrent attempts cannot slip past the limits together.
+func (g *Guard) Reserve(ctx context.Context, a Attempt) (*Reservation, error) {
+	r := &Reservation{attempt: a}
+	for _, k := range g.keys(a) {
+		var wait time.Duration
+		attempts, ok, err := g.store.Reserve(ctx, k.key, g.config.Window, func(attempts Attempts) bool {
+			wait = g.retryAfter(attempts, k.limit)
+			return wait == 0
+		})
+		if err == nil && !ok {
+			g.throttled(ctx, k.key, a, wait)
+			err = &ThrottledError{RetryAfter: wait}
+		}
+		if err != nil {
+			// Keys reserved so far are given back, as the attempt does not happen
+			if releaseErr := g.Release(ctx, r); releaseErr != nil {
+				log.Printf("Failed to release login attempt: %v", releaseErr)
+			}
+			return nil, err
+		}
+
+		r.failures = append(r.failures, attempts.Failures)
+	}
+
+	return r, nil
+}
+
+// Fail records that a reserved attempt failed.
+// Pass userID 0 when the account is unknown.
+func (g *Guard) Fail(ctx context.Context, r *Reservation, userID int32) {
+	g.record(ctx, userID, EventLoginFailed, r.attempt, "")
+
+	// Record the lockout once, for the attempt that reached the threshold
+	for i, k := range g.keys(r.attempt) {
+		if r.failures[i] == k.limit.LockoutAfter {
+			g.record(ctx, userID, k.lockEvent, r.attempt, fmt.Sprintf("locked for %s after %d failures", k.limit.LockoutDuration, r.failures[i]))
+		}
+	}
+}
+
+// Succeed clears the account's failure streak after a successful login.
+// The client address only gets its reserved attempt back, so one valid account
+// cannot be used to reset its streak.
+func (g *Guard) Succeed(ctx context.Context, r *Reservation, userID int32) error {
+	g.record(ctx, userID, EventLoginSucceeded, r.attempt, "")
+
+	keys := g.keys(r.attempt)
+	if err := g.store.Reset(ctx, keys[0].key); err != nil {
+		return err
+	}
+
+	return g.store.Release(ctx, keys[1].key)
+}
+
+// Release gives back a reserved attempt that neither failed nor completed the login,
+// like a correct password that still needs a two-factor code
+func (g *Guard) Release(ctx context.Context, r *Reservation) error {
+	keys := g.keys(r.attempt)
+	for i := range r.failures {
+		if err := g.store.Release(ctx, keys[i].key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// throttled records a throttled attempt, once per backoff of the key
+func (g *Guard) throttled(ctx context.Context, key string, a Attempt, wait time.Duration) {
+	first, err := g.store.Throttled(ctx, key)
+	if err != nil {
+		log.Printf("Failed to mark login throttled: %v", err)
+		return
+	}
+	if first {
+		g.record(ctx, 0, EventLoginThrottled, a, fmt.Sprintf("retry after %s", wait.Round(time.Second)))
+	}
+}
+
+// Prune forgets streaks that have expired, if the store supports it
+func (g *Guard) Prune(ctx context.Context) error {
+	p, ok := g.store.(interface {
+		Prune(ctx context.Context, before time.Time) error
+	})
+	if !ok {
+		return nil
+	}
+
+	return p.Prune(ctx, g.now().Add(-g.config.Window))
+}
+
+// retryAfter returns how long the key must wait before its next attempt
+func (g *Guard) retryAfter(a Attempts, limit Limit) time.Duration {
+	if a.Failures == 0 || g.now().Sub(a.LastFailure) > g.config.Window {
+		return 0
+	}
+
+	var until time.Time
+	switch {
+	case a.Failures >= limit.LockoutAfter:
+		until = a.LastFailure.Add(limit.LockoutDuration)
+	case a.Failures > limit.FreeAttempts:
+		delay := g.config.BaseDelay
+		for i := limit.FreeAttempts + 1; i < a.Failures && delay < g.config.MaxDelay; i++ {
+			delay *= 2
+		}
+		until = a.LastFailure.Add(min(delay, g.config.MaxDelay))
+	default:
+		return 0
+	}
+
+	return max(until.Sub(g.now()), 0)
+}
+
+// limitedKey is a counter key with the limit that applies to it
+type limitedKey struct {
+	key       string
+	limit     Limit
+	lockEvent string
+}
+
+// keys returns the counters an attempt counts towards, the account first
+func (g *Guard) keys(a Attempt) []limitedKey {
+	return []limitedKey{
+		{key: accountKey(a.Email), limit: g.config.Account, lockEvent: EventAccountLocked},
+		{key: "ip:" + a.IPAddress, limit: g.config.IP, lockEvent: EventAddressLocked},
+	}
+}
+
+// accountKey returns the counter key for an email; case is ignored so it cannot be used to dodge the limit
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// record writes a security event. Failures are logged but do not block the login.
+func (g *Guard) record(ctx context.Context, userID int32, eventType string, a Attempt, details string) {
+	err := g.queries.CreateSecurityEvent(ctx, db.CreateSecurityEventParams{
+		UserID:    pgtype.Int4{Int32: userID, Valid: userID != 0},
+		EventType: eventType,
+		Email:     a.Email,
+		IpAddress: a.IPAddress,
+		UserAgent: a.UserAgent,
+		Details:   details,
+	})
+	if err != nil {
+		log.Printf("Failed to record security event %s: %v", eventType, err)
+	}
+}
@@ -0,0 +1,156 @@
+package loginguard_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/services/loginguard"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockoutConfig locks an account out after five failures, without delays before that
+func lockoutConfig() loginguard.Config {
+	config := loginguard.DefaultConfig()
+	config.Account = loginguard.Limit{FreeAttempts: 5, LockoutAfter: 5, LockoutDuration: time.Hour}
+	return config
+}
+
+// forEachStore runs a test against both stores, sharing a database for security events
+func forEachStore(t *testing.T, fn func(t *testing.T, pool *pgxpool.Pool, store loginguard.Store)) {
+	pool := dbtest.New(t)
+	queries := db.New(pool)
+
+	t.Run("memory", func(t *testing.T) {
+		fn(t, pool, loginguard.NewMemoryStore())
+	})
+	t.Run("postgres", func(t *testing.T) {
+		fn(t, pool, loginguard.NewPostgresStore(queries))
+	})
+}
+
+func TestReserveConcurrently(t *testing.T) {
+	forEachStore(t, func(t *testing.T, pool *pgxpool.Pool, store loginguard.Store) {
+		ctx := context.Background()
+		guard := loginguard.New(store, db.New(pool), lockoutConfig())
+		attempt := loginguard.Attempt{Email: t.Name() + "@example.com", IPAddress: "203.0.113.7"}
+
+		// Every attempt checks the streak at once; only the first five may get through
+		const n = 40
+		reservations := make([]*loginguard.Reservation, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reservations[i], errs[i] = guard.Reserve(ctx, attempt)
+			}()
+		}
+		wg.Wait()
+
+		var reserved []*loginguard.Reservation
+		for i, err := range errs {
+			var throttled *loginguard.ThrottledError
+			switch {
+			case err == nil:
+				reserved = append(reserved, reservations[i])
+			case !errors.As(err, &throttled):
+				t.Fatalf("Reserve() error = %v", err)
+			}
+		}
+		if len(reserved) != 5 {
+			t.Fatalf("%d attempts got through, want 5", len(reserved))
+		}
+
+		// The account is locked out, but the throttle is recorded only once
+		var events int
+		err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM security_events WHERE email = $1 AND event_type = $2`,
+			attempt.Email, loginguard.EventLoginThrottled).Scan(&events)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if events != 1 {
+			t.Errorf("%d throttle events recorded, want 1", events)
+		}
+
+		// Attempts that did not fail are given back
+		for _, r := range reserved {
+			if err := guard.Release(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, err := guard.Reserve(ctx, attempt)
+		if err != nil {
+			t.Fatalf("Reserve() after release error = %v", err)
+		}
+		if err := guard.Succeed(ctx, r, 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestFailRecordsLockoutOnce(t *testing.T) {
+	forEachStore(t, func(t *testing.T, pool *pgxpool.Pool, store loginguard.Store) {
+		ctx := context.Background()
+		guard := loginguard.New(store, db.New(pool), lockoutConfig())
+		attempt := loginguard.Attempt{Email: t.Name() + "@example.com", IPAddress: "203.0.113.8"}
+
+		for i := 0; i < 5; i++ {
+			r, err := guard.Reserve(ctx, attempt)
+			if err != nil {
+				t.Fatalf("Reserve() %d error = %v", i, err)
+			}
+			guard.Fail(ctx, r, 0)
+		}
+		if _, err := guard.Reserve(ctx, attempt); err == nil {
+			t.Fatal("Reserve() succeeded on a locked out account")
+		}
+
+		var events int
+		err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM security_events WHERE email = $1 AND event_type = $2`,
+			attempt.Email, loginguard.EventAccountLocked).Scan(&events)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if events != 1 {
+			t.Errorf("%d lockout events recorded, want 1", events)
+		}
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := loginguard.NewLimiter(loginguard.NewMemoryStore(), "reset", loginguard.LimiterConfig{PerEmail: 2, PerIP: 3, Window: time.Hour})
+	allow := func(email, ip string) error {
+		return limiter.Allow(ctx, loginguard.Attempt{Email: email, IPAddress: ip})
+	}
+
+	// Each address gets two emails, however the case of the address is written
+	for _, email := range []string{"a@example.com", "A@example.com"} {
+		if err := allow(email, "203.0.113.1"); err != nil {
+			t.Fatalf("Allow(%s) error = %v", email, err)
+		}
+	}
+	var throttled *loginguard.ThrottledError
+	if err := allow("a@example.com", "203.0.113.2"); !errors.As(err, &throttled) || throttled.RetryAfter <= 0 {
+		t.Fatalf("third email to the address: error = %v, want a *ThrottledError", err)
+	}
+
+	// The first client address has one request left, then it is throttled for every address
+	if err := allow("b@example.com", "203.0.113.1"); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if err := allow("c@example.com", "203.0.113.1"); !errors.As(err, &throttled) {
+		t.Fatalf("fourth request from the address: error = %v, want a *ThrottledError", err)
+	}
+
+	// The refused request was not charged to the email address
+	if err := allow("c@example.com", "203.0.113.3"); err != nil {
+		t.Errorf("Allow() after a refused request error = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package loginguard
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// LimiterConfig holds how often an action may be repeated
+type LimiterConfig struct {
+	// PerEmail is how many times the action may be done for one email address within Window
+	PerEmail int
+	// PerIP is how many times one client address may do the action within Window
+	PerIP int
+	// Window is how long a use counts; it restarts with every allowed use
+	Window time.Duration
+}
+
+// Limiter throttles an action that is not a login, like requesting password reset emails,
+// per email address and per client address. It counts in the same store as the Guard.
+type Limiter struct {
+	store  Store
+	name   string
+	config LimiterConfig
+	now    func() time.Time
+}
+
+// NewLimiter creates a Limiter. name keeps its counters apart from other users of the store.
+func NewLimiter(store Store, name string, config LimiterConfig) *Limiter {
+	return &Limiter{
+		store:  store,
+		name:   name,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Allow counts a use of the action, or returns a *ThrottledError when the email
+// address or the client address has used up its allowance
+func (l *Limiter) Allow(ctx context.Context, a Attempt) error {
+	keys := []struct {
+		key   string
+		limit int
+	}{
+		{key: l.name + ":" + accountKey(a.Email), limit: l.config.PerEmail},
+		{key: l.name + ":ip:" + a.IPAddress, limit: l.config.PerIP},
+	}
+
+	for i, k := range keys {
+		var wait time.Duration
+		_, ok, err := l.store.Reserve(ctx, k.key, l.config.Window, func(attempts Attempts) bool {
+			if attempts.Failures >= k.limit {
+				wait = max(attempts.LastFailure.Add(l.config.Window).Sub(l.now()), 0)
+			}
+			return wait == 0
+		})
+		if err == nil && !ok {
+			err = &ThrottledError{RetryAfter: wait, message: "too many requests, try again later"}
+		}
+		if err != nil {
+			// The email address is not charged for a use the client address may not make
+			if i > 0 {
+				if releaseErr := l.store.Release(ctx, keys[0].key); releaseErr != nil {
+					log.Printf("Failed to release %s use: %v", l.name, releaseErr)
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
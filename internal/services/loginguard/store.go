@@ -0,0 +1,209 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Attempts is the failure streak recorded for a key
+type Attempts struct {
+	Failures    int
+	LastFailure time.Time
+}
+
+// Store keeps failed login counters.
+// Use the Postgres store when several server instances must share the counters.
+type Store interface {
+	// Reserve counts an attempt as a failed one before its outcome is known, if allow
+	// accepts the current streak. Checking and counting happen atomically, so concurrent
+	// attempts cannot all pass the same check. It returns the updated streak and true,
+	// or the unchanged streak and false when allow refuses.
+	// Failures older than window no longer count towards the streak.
+	Reserve(ctx context.Context, key string, window time.Duration, allow func(Attempts) bool) (Attempts, bool, error)
+	// Release takes back a reserved attempt that did not fail
+	Release(ctx context.Context, key string) error
+	// Throttled marks the streak as throttled. It returns true only for the first
+	// call since the last failure, so a backoff is reported once.
+	Throttled(ctx context.Context, key string) (bool, error)
+	// Reset clears the streak
+	Reset(ctx context.Context, key string) error
+}
+
+// memoryAttempts is a streak kept by the MemoryStore
+type memoryAttempts struct {
+	Attempts
+	throttledAt time.Time
+}
+
+// MemoryStore keeps counters in memory. It suits a single server instance.
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]memoryAttempts
+	now      func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts: make(map[string]memoryAttempts),
+		now:      time.Now,
+	}
+}
+
+// Reserve counts an attempt if allow accepts the streak
+func (s *MemoryStore) Reserve(ctx context.Context, key string, window time.Duration, allow func(Attempts) bool) (Attempts, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.attempts[key]
+	if !allow(a.Attempts) {
+		return a.Attempts, false, nil
+	}
+
+	now := s.now()
+	if now.Sub(a.LastFailure) > window {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailure = now
+	s.attempts[key] = a
+
+	return a.Attempts, true, nil
+}
+
+// Release takes back a reserved attempt
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.attempts[key]; ok && a.Failures > 0 {
+		a.Failures--
+		s.attempts[key] = a
+	}
+	return nil
+}
+
+// Throttled marks the streak as throttled
+func (s *MemoryStore) Throttled(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if !ok || !a.throttledAt.IsZero() && !a.throttledAt.Before(a.LastFailure) {
+		return false, nil
+	}
+	a.throttledAt = s.now()
+	s.attempts[key] = a
+
+	return true, nil
+}
+
+// Reset clears the streak
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// Prune forgets streaks whose last failure is older than the cutoff, so the map does not grow forever
+func (s *MemoryStore) Prune(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, a := range s.attempts {
+		if a.LastFailure.Before(before) {
+			delete(s.attempts, key)
+		}
+	}
+	return nil
+}
+
+// PostgresStore keeps counters in the login_attempts table
+type PostgresStore struct {
+	queries *db.Queries
+}
+
+// NewPostgresStore creates a store backed by Postgres
+func NewPostgresStore(queries *db.Queries) *PostgresStore {
+	return &PostgresStore{
+		queries: queries,
+	}
+}
+
+// Reserve counts an attempt if allow accepts the streak.
+// The streak's row stays locked from the check until the attempt is counted.
+func (s *PostgresStore) Reserve(ctx context.Context, key string, window time.Duration, allow func(Attempts) bool) (Attempts, bool, error) {
+	var attempts Attempts
+	var ok bool
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		row, err := q.LockLoginAttempts(ctx, key)
+		if err != nil {
+			return err
+		}
+		attempts = Attempts{Failures: int(row.Failures), LastFailure: row.LastFailureAt.Time}
+
+		if ok = allow(attempts); !ok {
+			return nil
+		}
+
+		updated, err := q.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+			Key:           key,
+			WindowSeconds: window.Seconds(),
+		})
+		if err != nil {
+			return err
+		}
+		attempts = Attempts{Failures: int(updated.Failures), LastFailure: updated.LastFailureAt.Time}
+		return nil
+	})
+	if err != nil {
+		return Attempts{}, false, fmt.Errorf("failed to reserve login attempt: %w", err)
+	}
+
+	return attempts, ok, nil
+}
+
+// Release takes back a reserved attempt
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	if err := s.queries.ReleaseLoginAttempt(ctx, key); err != nil {
+		return fmt.Errorf("failed to release login attempt: %w", err)
+	}
+
+	return nil
+}
+
+// Throttled marks the streak as throttled
+func (s *PostgresStore) Throttled(ctx context.Context, key string) (bool, error) {
+	rows, err := s.queries.MarkLoginThrottled(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark login throttled: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Reset clears the streak
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	if err := s.queries.DeleteLoginAttempts(ctx, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// Prune deletes streaks whose last failure is older than the cutoff
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) error {
+	err := s.queries.DeleteStaleLoginAttempts(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to prune login attempts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// KindDueReminder notifies an assignee that a card is coming due
+const KindDueReminder = "due_reminder"
+
+// Notification is a message to one user, usually about a card
+type Notification struct {
+	UserID int32
+	Email  string
+	// CardID is 0 for notifications that are not about a card
+	CardID  int32
+	Kind    string
+	Subject string
+	Message string
+}
+
+// Service records in-app notifications and emails them
+type Service struct {
+	queries *db.Queries
+	mailer  mail.Mailer
+}
+
+// New creates a new notification service
+func New(queries *db.Queries, mailer mail.Mailer) *Service {
+	return &Service{
+		queries: queries,
+		mailer:  mailer,
+	}
+}
+
+// Create records an in-app notification with q, so callers can make it part of a transaction.
+// Emails are sent separately with Email once the transaction has committed.
+func (s *Service) Create(ctx context.Context, q *db.Queries, n Notification) error {
+	_, err := q.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:  n.UserID,
+		CardID:  pgtype.Int4{Int32: n.CardID, Valid: n.CardID != 0},
+		Kind:    n.Kind,
+		Message: n.Message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// Email sends a notification by email. Delivery is best effort: a failure is logged
+// and the in-app notification is kept.
+func (s *Service) Email(ctx context.Context, n Notification) {
+	err := s.mailer.Send(ctx, mail.Message{
+		To:      n.Email,
+		Subject: oneLine(n.Subject),
+		Body:    n.Message,
+	})
+	if err != nil {
+		log.Printf("Failed to email %s notification to user %d: %v", n.Kind, n.UserID, err)
+	}
+}
+
+// GetNotifications gets the user's most recent notifications, newest first.
+// Only unread ones are listed when unreadOnly is true.
+func (s *Service) GetNotifications(ctx context.Context, userID int32, unreadOnly bool) ([]db.Notification, error) {
+	notifications, err := s.queries.GetUserNotifications(ctx, db.GetUserNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if notifications == nil {
+		return []db.Notification{}, nil
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *Service) MarkRead(ctx context.Context, userID, notificationID int32) (*db.Notification, error) {
+	notification, err := s.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	return &notification, nil
+}
+
+// oneLine replaces line breaks and other control characters, which may come from
+// card titles, with spaces
+func oneLine(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,89 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/notification"
+)
+
+// Service sends due date reminders to card assignees
+type Service struct {
+	queries  *db.Queries
+	notifier *notification.Service
+}
+
+// New creates a new reminder service
+func New(queries *db.Queries, notifier *notification.Service) *Service {
+	return &Service{
+		queries:  queries,
+		notifier: notifier,
+	}
+}
+
+// DispatchDue sends the reminders that are due, at most max of them, and returns how many were sent.
+// Each reminder is claimed with SKIP LOCKED, and its in-app notifications are recorded and the
+// reminder marked as sent in the same transaction, so instances running side by side never send
+// the same reminder twice. Emails go out only after that commits, so no lock is held during SMTP
+// and a failed commit sends nothing; a crash in between loses the emails but not the notifications.
+// Reminders for cards without assignees are marked without notifying anyone.
+func (s *Service) DispatchDue(ctx context.Context, max int) (int, error) {
+	sent := 0
+	for sent < max {
+		done := false
+		var pending []notification.Notification
+		err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+			pending = nil
+
+			reminder, err := q.LockDueReminder(ctx)
+			if err != nil {
+				if errs.IsNoRows(err) {
+					done = true
+					return nil
+				}
+				return err
+			}
+
+			assignees, err := q.GetCardAssignees(ctx, reminder.CardID)
+			if err != nil {
+				return err
+			}
+
+			for _, assignee := range assignees {
+				n := notification.Notification{
+					UserID:  assignee.UserID,
+					Email:   assignee.Email,
+					CardID:  reminder.CardID,
+					Kind:    notification.KindDueReminder,
+					Subject: fmt.Sprintf("Reminder: %s is due soon", reminder.CardTitle),
+					Message: fmt.Sprintf("%q on the board %q is due %s. You are assigned to it.",
+						reminder.CardTitle, reminder.BoardName, reminder.DueAt.Time.UTC().Format("Mon 2 Jan 2006 15:04 MST")),
+				}
+				if err := s.notifier.Create(ctx, q, n); err != nil {
+					return err
+				}
+				pending = append(pending, n)
+			}
+
+			return q.MarkReminderSent(ctx, db.MarkReminderSentParams{
+				SentForDueAt: reminder.DueAt,
+				ID:           reminder.ID,
+			})
+		})
+		if err != nil {
+			return sent, fmt.Errorf("failed to dispatch reminder: %w", err)
+		}
+		if done {
+			break
+		}
+
+		for _, n := range pending {
+			s.notifier.Email(ctx, n)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,255 @@
+package reminder_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/services/board"
+	"github.com/anubhav047/goboard/internal/services/card"
+	"github.com/anubhav047/goboard/internal/services/list"
+	"github.com/anubhav047/goboard/internal/services/notification"
+	"github.com/anubhav047/goboard/internal/services/reminder"
+)
+
+// recordingMailer keeps the messages it is asked to send
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent)
+}
+
+// fixture is a user owning a board with one list
+type fixture struct {
+	queries   *db.Queries
+	cards     *card.Service
+	reminders *reminder.Service
+	mailer    *recordingMailer
+	userID    int32
+	listID    int32
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	pool := dbtest.New(t)
+	queries := db.New(pool)
+	mailer := &recordingMailer{}
+
+	user, err := queries.CreateUser(ctx, db.CreateUserParams{Name: "Test", Email: "test@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := board.New(queries).CreateBoard(ctx, "Board", "", user.ID, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := list.New(queries).CreateList(ctx, user.ID, "List", b.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fixture{
+		queries:   queries,
+		cards:     card.New(queries),
+		reminders: reminder.New(queries, notification.New(queries, mailer)),
+		mailer:    mailer,
+		userID:    user.ID,
+		listID:    l.ID,
+	}
+}
+
+// dueCard creates a card due after the given time, with a reminder the given time before that.
+// The user is assigned to it when assign is true.
+func (f fixture) dueCard(t *testing.T, due, before time.Duration, assign bool) int32 {
+	t.Helper()
+	ctx := context.Background()
+
+	c, err := f.cards.CreateCard(ctx, f.userID, "Card", "", f.listID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.setDue(t, c.ID, due)
+	if _, err := f.cards.AddReminder(ctx, f.userID, c.ID, before); err != nil {
+		t.Fatal(err)
+	}
+	if assign {
+		if _, err := f.cards.Assign(ctx, f.userID, c.ID, f.userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c.ID
+}
+
+func (f fixture) setDue(t *testing.T, cardID int32, due time.Duration) {
+	t.Helper()
+	dueAt := time.Now().Add(due)
+	if _, err := f.cards.UpdateCard(context.Background(), f.userID, cardID, "Card", "", card.Schedule{DueAt: &dueAt}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f fixture) dispatch(t *testing.T, max int) int {
+	t.Helper()
+	sent, err := f.reminders.DispatchDue(context.Background(), max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sent
+}
+
+func (f fixture) notifications(t *testing.T) int {
+	t.Helper()
+	notifications, err := f.queries.GetUserNotifications(context.Background(), db.GetUserNotificationsParams{UserID: f.userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(notifications)
+}
+
+func TestDispatchDue(t *testing.T) {
+	f := setup(t)
+	cardID := f.dueCard(t, 30*time.Minute, time.Hour, true)
+	// Not within its reminder offset yet
+	f.dueCard(t, 3*time.Hour, time.Hour, true)
+
+	if sent := f.dispatch(t, 10); sent != 1 {
+		t.Fatalf("DispatchDue sent %d reminders, want 1", sent)
+	}
+	if n := f.mailer.count(); n != 1 {
+		t.Errorf("%d emails sent, want 1", n)
+	} else if to := f.mailer.sent[0].To; to != "test@example.com" {
+		t.Errorf("email sent to %s, want test@example.com", to)
+	}
+	if n := f.notifications(t); n != 1 {
+		t.Errorf("%d notifications recorded, want 1", n)
+	}
+
+	// A reminder fires once per due date
+	if sent := f.dispatch(t, 10); sent != 0 {
+		t.Errorf("second DispatchDue sent %d reminders, want 0", sent)
+	}
+
+	// Moving the due date re-arms it
+	f.setDue(t, cardID, 45*time.Minute)
+	if sent := f.dispatch(t, 10); sent != 1 {
+		t.Errorf("DispatchDue after moving the due date sent %d reminders, want 1", sent)
+	}
+	if n := f.mailer.count(); n != 2 {
+		t.Errorf("%d emails sent, want 2", n)
+	}
+}
+
+func TestDispatchDueSkipsClosedCards(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	completed := f.dueCard(t, 30*time.Minute, time.Hour, true)
+	done := true
+	if _, err := f.cards.UpdateCard(ctx, f.userID, completed, "Card", "", card.Schedule{Completed: &done}); err != nil {
+		t.Fatal(err)
+	}
+
+	archived := f.dueCard(t, 30*time.Minute, time.Hour, true)
+	if _, err := f.cards.SetArchived(ctx, f.userID, archived, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := f.dispatch(t, 10); sent != 0 {
+		t.Errorf("DispatchDue sent %d reminders for closed cards, want 0", sent)
+	}
+}
+
+// TestDispatchDueWithoutAssignees checks that reminders with nobody to notify are marked as sent
+func TestDispatchDueWithoutAssignees(t *testing.T) {
+	f := setup(t)
+	f.dueCard(t, 30*time.Minute, time.Hour, false)
+
+	if sent := f.dispatch(t, 10); sent != 1 {
+		t.Errorf("DispatchDue sent %d reminders, want 1", sent)
+	}
+	if n := f.mailer.count(); n != 0 {
+		t.Errorf("%d emails sent, want 0", n)
+	}
+	if sent := f.dispatch(t, 10); sent != 0 {
+		t.Errorf("second DispatchDue sent %d reminders, want 0", sent)
+	}
+}
+
+func TestDispatchDueMax(t *testing.T) {
+	f := setup(t)
+	for i := 0; i < 3; i++ {
+		f.dueCard(t, time.Duration(10+i)*time.Minute, time.Hour, true)
+	}
+
+	if sent := f.dispatch(t, 2); sent != 2 {
+		t.Errorf("DispatchDue sent %d reminders, want 2", sent)
+	}
+	if sent := f.dispatch(t, 2); sent != 1 {
+		t.Errorf("DispatchDue sent %d reminders, want the remaining 1", sent)
+	}
+}
+
+// TestDispatchDueConcurrently checks that instances running side by side send each reminder once
+func TestDispatchDueConcurrently(t *testing.T) {
+	f := setup(t)
+	const reminders, instances = 10, 4
+	for i := 0; i < reminders; i++ {
+		f.dueCard(t, time.Duration(10+i)*time.Minute, time.Hour, true)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	start := make(chan struct{})
+	sent := make([]int, instances)
+	errs := make([]error, instances)
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			sent[i], errs[i] = f.reminders.DispatchDue(ctx, reminders)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	total := 0
+	for i := range sent {
+		if errs[i] != nil {
+			t.Errorf("instance %d: %v", i, errs[i])
+		}
+		total += sent[i]
+	}
+	if total != reminders {
+		t.Errorf("instances sent %d reminders in total, want %d", total, reminders)
+	}
+	if n := f.mailer.count(); n != reminders {
+		t.Errorf("%d emails sent, want %d", n, reminders)
+	}
+	if n := f.notifications(t); n != reminders {
+		t.Errorf("%d notifications recorded, want %d", n, reminders)
+	}
+	if sent := f.dispatch(t, reminders); sent != 0 {
+		t.Errorf("DispatchDue afterwards sent %d reminders, want 0", sent)
+	}
+}
@@ -0,0 +1,159 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+)
+
+// ErrSessionNotFound is returned when the session does not exist or belongs to another user
+var ErrSessionNotFound = errs.New(errs.NotFound, "session_not_found", "session not found")
+
+// Service keeps an index of each user's sessions so they can be listed and revoked.
+// The session data itself lives in the scs sessions table.
+type Service struct {
+	queries *db.Queries
+}
+
+// New creates a new session service
+func New(queries *db.Queries) *Service {
+	return &Service{
+		queries: queries,
+	}
+}
+
+// Track records a session for the user, or refreshes its last seen time.
+// Refreshes are throttled to once a minute per session.
+func (s *Service) Track(ctx context.Context, userID int32, token, ipAddress, userAgent string) error {
+	err := s.queries.TrackUserSession(ctx, db.TrackUserSessionParams{
+		UserID:    userID,
+		Token:     token,
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		Device:    describeDevice(userAgent),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to track session: %w", err)
+	}
+
+	return nil
+}
+
+// List gets the user's active sessions, flagging the one with the current token
+func (s *Service) List(ctx context.Context, userID int32, currentToken string) ([]db.GetUserSessionsRow, error) {
+	sessions, err := s.queries.GetUserSessions(ctx, db.GetUserSessionsParams{
+		UserID:       userID,
+		CurrentToken: currentToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if sessions == nil {
+		return []db.GetUserSessionsRow{}, nil
+	}
+
+	return sessions, nil
+}
+
+// Revoke ends one of the user's sessions
+func (s *Service) Revoke(ctx context.Context, userID, sessionID int32) error {
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		token, err := q.DeleteUserSession(ctx, db.DeleteUserSessionParams{
+			ID:     sessionID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteSession(ctx, token)
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeOthers ends every session of the user except the one with the given token
+func (s *Service) RevokeOthers(ctx context.Context, userID int32, currentToken string) error {
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		return RevokeAll(ctx, q, userID, currentToken)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// Forget removes a session from the index, e.g. when the user logs out
+func (s *Service) Forget(ctx context.Context, token string) error {
+	if err := s.queries.DeleteUserSessionByToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to forget session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAll ends every session of the user except the one with the given token.
+// Pass an empty token to end all of them. It is exported so other services can
+// revoke sessions inside their own transactions.
+func RevokeAll(ctx context.Context, q *db.Queries, userID int32, exceptToken string) error {
+	err := q.DeleteOtherSessions(ctx, db.DeleteOtherSessionsParams{
+		UserID: userID,
+		Token:  exceptToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.DeleteOtherUserSessions(ctx, db.DeleteOtherUserSessionsParams{
+		UserID: userID,
+		Token:  exceptToken,
+	})
+}
+
+// describeDevice turns a user agent into a short description like "Firefox on Linux"
+func describeDevice(userAgent string) string {
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		// Order matters: Edge and Opera user agents also mention Chrome, and Chrome mentions Safari
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	os := "unknown OS"
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			os = o.name
+			break
+		}
+	}
+
+	return browser + " on " + os
+}
@@ -0,0 +1,289 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrUnknownProvider is returned for providers that are not configured
+	ErrUnknownProvider = errs.New(errs.NotFound, "unknown_provider", "unknown identity provider")
+	// ErrInvalidState is returned when the callback does not match the login that was started
+	ErrInvalidState = errs.New(errs.Validation, "invalid_login_state", "invalid or expired login state")
+	// ErrMissingEmail is returned when the identity provider does not share an email address
+	ErrMissingEmail = errs.New(errs.Validation, "missing_email", "identity provider did not return an email address")
+	// ErrEmailNotVerified is returned when an unverified external email matches an existing account
+	ErrEmailNotVerified = errs.New(errs.Conflict, "email_not_verified", "identity provider has not verified this email address")
+	// ErrAccountNotVerified is returned when the existing account with the email has not verified it
+	ErrAccountNotVerified = errs.New(errs.Conflict, "account_not_verified", "an account with this email address exists but has not verified it; verify it before signing in with this provider")
+)
+
+// ProviderConfig describes an OpenID Connect identity provider
+type ProviderConfig struct {
+	// Name identifies the provider in URLs, e.g. "corp"
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback URL registered with the provider
+	RedirectURL string
+	// Scopes are requested in addition to "openid"; defaults to email and profile
+	Scopes []string
+}
+
+// provider is a configured identity provider ready for logins
+type provider struct {
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// Login holds the values that must be kept in the session between
+// starting a login and handling its callback
+type Login struct {
+	State    string
+	Verifier string
+	Nonce    string
+}
+
+// claims are the ID token claims used to find or create the user
+type claims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Service signs users in through OpenID Connect using the authorization code flow with PKCE
+type Service struct {
+	queries   *db.Queries
+	providers map[string]*provider
+}
+
+// New creates a new SSO service. It fetches each issuer's discovery document,
+// so the providers must be reachable.
+func New(ctx context.Context, queries *db.Queries, configs []ProviderConfig) (*Service, error) {
+	providers := make(map[string]*provider, len(configs))
+	for _, cfg := range configs {
+		p, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover provider %s: %w", cfg.Name, err)
+		}
+
+		scopes := cfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"email", "profile"}
+		}
+
+		providers[cfg.Name] = &provider{
+			oauth: oauth2.Config{
+				ClientID:     cfg.ClientID,
+				ClientSecret: cfg.ClientSecret,
+				RedirectURL:  cfg.RedirectURL,
+				Endpoint:     p.Endpoint(),
+				Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+			},
+			verifier: p.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		}
+	}
+
+	return &Service{
+		queries:   queries,
+		providers: providers,
+	}, nil
+}
+
+// Providers lists the names of the configured providers
+func (s *Service) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start begins a login and returns the provider's authorization URL.
+// The returned Login must be stored in the session until the callback.
+func (s *Service) Start(name string) (string, Login, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return "", Login{}, ErrUnknownProvider
+	}
+
+	state, _, err := token.Generate()
+	if err != nil {
+		return "", Login{}, err
+	}
+	nonce, _, err := token.Generate()
+	if err != nil {
+		return "", Login{}, err
+	}
+
+	login := Login{
+		State:    state,
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    nonce,
+	}
+	url := p.oauth.AuthCodeURL(state, oauth2.S256ChallengeOption(login.Verifier), oidc.Nonce(nonce))
+	return url, login, nil
+}
+
+// Finish exchanges the authorization code, verifies the ID token and returns the
+// matching user. Unknown identities are linked to an existing user with the same
+// email when both the provider and the user have verified it, or a new user is created for them.
+func (s *Service) Finish(ctx context.Context, name string, login Login, state, code string) (db.User, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return db.User{}, ErrUnknownProvider
+	}
+	if login.State == "" || state != login.State {
+		return db.User{}, ErrInvalidState
+	}
+
+	tok, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return db.User{}, errors.New("token response did not include an id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to verify id token: %w", err)
+	}
+	if idToken.Nonce != login.Nonce {
+		return db.User{}, ErrInvalidState
+	}
+
+	var c claims
+	if err := idToken.Claims(&c); err != nil {
+		return db.User{}, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+	c.Email = strings.TrimSpace(c.Email)
+	if c.Email == "" {
+		return db.User{}, ErrMissingEmail
+	}
+
+	return s.resolveUser(ctx, name, c)
+}
+
+// resolveUser finds the user linked to the identity, linking or provisioning one if needed
+func (s *Service) resolveUser(ctx context.Context, name string, c claims) (db.User, error) {
+	var user db.User
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = q.GetUserByIdentity(ctx, db.GetUserByIdentityParams{
+			Provider: name,
+			Subject:  c.Subject,
+		})
+		if err == nil {
+			return q.TouchUserIdentity(ctx, db.TouchUserIdentityParams{
+				Provider: name,
+				Subject:  c.Subject,
+				Email:    c.Email,
+			})
+		}
+		if !errs.IsNoRows(err) {
+			return err
+		}
+
+		user, err = q.GetUserByEmail(ctx, c.Email)
+		switch {
+		case err == nil:
+			// Only a verified email proves the external identity owns the account
+			if !c.EmailVerified {
+				return ErrEmailNotVerified
+			}
+			// The local account must have proven it too. Otherwise whoever registered the
+			// address first would keep their password on the account once it is linked.
+			if !user.EmailVerifiedAt.Valid {
+				return ErrAccountNotVerified
+			}
+		case errs.IsNoRows(err):
+			user, err = provisionUser(ctx, q, c)
+			if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		return q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: name,
+			Subject:  c.Subject,
+			Email:    c.Email,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailNotVerified) || errors.Is(err, ErrAccountNotVerified) {
+			return db.User{}, err
+		}
+		return db.User{}, fmt.Errorf("failed to resolve user: %w", err)
+	}
+
+	return user, nil
+}
+
+// provisionUser creates a user for a new external identity.
+// The account gets a random password hash and is marked as having no password,
+// so it can only sign in through SSO until the user sets a password via the reset flow.
+func provisionUser(ctx context.Context, q *db.Queries, c claims) (db.User, error) {
+	password, _, err := token.Generate()
+	if err != nil {
+		return db.User{}, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	name := c.Name
+	if name == "" {
+		name, _, _ = strings.Cut(c.Email, "@")
+	}
+
+	user, err := q.CreateUser(ctx, db.CreateUserParams{
+		Name:           name,
+		Email:          c.Email,
+		HashedPassword: string(hashedPassword),
+		HasPassword:    false,
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	// Board invitations sent to the email are only attached when the provider vouches for it;
+	// otherwise the user verifies the address like any other account.
+	if !c.EmailVerified {
+		return user, nil
+	}
+
+	user, err = q.MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{
+		ID:    user.ID,
+		Email: user.Email,
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	err = q.AttachInvitationsToUser(ctx, db.AttachInvitationsToUserParams{
+		InviteeID: pgtype.Int4{Int32: user.ID, Valid: true},
+		Email:     user.Email,
+	})
+	return user, err
+}
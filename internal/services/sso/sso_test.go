@@ -0,0 +1,217 @@
+package sso_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/services/sso"
+	"github.com/anubhav047/goboard/internal/services/sso/ssotest"
+)
+
+// newService starts a mock issuer and an SSO service configured with it as "mock"
+func newService(t *testing.T, queries *db.Queries) (*sso.Service, *ssotest.Issuer) {
+	t.Helper()
+
+	issuer, err := ssotest.NewIssuer("goboard", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(issuer.Close)
+
+	service, err := sso.New(context.Background(), queries, []sso.ProviderConfig{{
+		Name:         "mock",
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  "http://goboard.test/api/auth/oidc/mock/callback",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service, issuer
+}
+
+// signIn runs a complete login against the issuer as the given identity
+func signIn(t *testing.T, service *sso.Service, issuer *ssotest.Issuer, identity ssotest.Identity) (db.User, error) {
+	t.Helper()
+
+	issuer.SignInAs(identity)
+	authURL, login, err := service.Start("mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service.Finish(context.Background(), "mock", login, state, code)
+}
+
+// createUser registers a password account, optionally with a verified email
+func createUser(t *testing.T, queries *db.Queries, email string, verified bool) db.User {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := queries.CreateUser(ctx, db.CreateUserParams{
+		Name:           "Local",
+		Email:          email,
+		HashedPassword: "x",
+		HasPassword:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified {
+		user, err = queries.MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{ID: user.ID, Email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return user
+}
+
+func TestFinishProvisionsUser(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	service, issuer := newService(t, queries)
+
+	identity := ssotest.Identity{Subject: "alice-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	user, err := signIn(t, service, issuer, identity)
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if user.Email != identity.Email || user.Name != identity.Name {
+		t.Errorf("provisioned user = %q <%s>, want %q <%s>", user.Name, user.Email, identity.Name, identity.Email)
+	}
+	if !user.EmailVerifiedAt.Valid {
+		t.Error("provisioned user email is not verified")
+	}
+
+	// The identity is linked, so later logins find the same user even with a new email
+	identity.Email = "alice@new.example.com"
+	again, err := signIn(t, service, issuer, identity)
+	if err != nil {
+		t.Fatalf("second Finish() error = %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login user = %d, want %d", again.ID, user.ID)
+	}
+}
+
+func TestFinishProvisionsUnverifiedEmail(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	service, issuer := newService(t, queries)
+
+	user, err := signIn(t, service, issuer, ssotest.Identity{Subject: "bob-1", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if user.EmailVerifiedAt.Valid {
+		t.Error("email the provider did not verify was marked verified")
+	}
+	if user.Name != "bob" {
+		t.Errorf("name = %q, want the email's local part", user.Name)
+	}
+}
+
+func TestFinishLinksVerifiedAccount(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	service, issuer := newService(t, queries)
+	local := createUser(t, queries, "carol@example.com", true)
+
+	user, err := signIn(t, service, issuer, ssotest.Identity{Subject: "carol-1", Email: "carol@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if user.ID != local.ID {
+		t.Errorf("linked user = %d, want existing user %d", user.ID, local.ID)
+	}
+
+	linked, err := queries.GetUserByIdentity(context.Background(), db.GetUserByIdentityParams{Provider: "mock", Subject: "carol-1"})
+	if err != nil || linked.ID != local.ID {
+		t.Errorf("identity not linked to user %d: %v", local.ID, err)
+	}
+}
+
+func TestFinishRefusesUnverifiedAccount(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	service, issuer := newService(t, queries)
+
+	// Someone registered the address first without proving they own it
+	createUser(t, queries, "dave@example.com", false)
+
+	_, err := signIn(t, service, issuer, ssotest.Identity{Subject: "dave-1", Email: "dave@example.com", EmailVerified: true})
+	if !errors.Is(err, sso.ErrAccountNotVerified) {
+		t.Fatalf("Finish() error = %v, want %v", err, sso.ErrAccountNotVerified)
+	}
+
+	_, err = queries.GetUserByIdentity(context.Background(), db.GetUserByIdentityParams{Provider: "mock", Subject: "dave-1"})
+	if err == nil {
+		t.Error("identity was linked to the unverified account")
+	}
+}
+
+func TestFinishRefusesUnverifiedProviderEmail(t *testing.T) {
+	queries := db.New(dbtest.New(t))
+	service, issuer := newService(t, queries)
+	createUser(t, queries, "erin@example.com", true)
+
+	_, err := signIn(t, service, issuer, ssotest.Identity{Subject: "erin-1", Email: "erin@example.com"})
+	if !errors.Is(err, sso.ErrEmailNotVerified) {
+		t.Fatalf("Finish() error = %v, want %v", err, sso.ErrEmailNotVerified)
+	}
+}
+
+func TestFinishRejectsWrongState(t *testing.T) {
+	service, issuer := newService(t, nil)
+	issuer.SignInAs(ssotest.Identity{Subject: "frank-1", Email: "frank@example.com", EmailVerified: true})
+
+	authURL, login, err := service.Start("mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, code, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.Finish(context.Background(), "mock", login, "forged", code)
+	if !errors.Is(err, sso.ErrInvalidState) {
+		t.Fatalf("Finish() error = %v, want %v", err, sso.ErrInvalidState)
+	}
+}
+
+func TestFinishRejectsWrongNonce(t *testing.T) {
+	service, issuer := newService(t, nil)
+	issuer.ForceNonce("replayed")
+
+	_, err := signIn(t, service, issuer, ssotest.Identity{Subject: "grace-1", Email: "grace@example.com", EmailVerified: true})
+	if !errors.Is(err, sso.ErrInvalidState) {
+		t.Fatalf("Finish() error = %v, want %v", err, sso.ErrInvalidState)
+	}
+}
+
+func TestFinishRejectsWrongVerifier(t *testing.T) {
+	service, issuer := newService(t, nil)
+	issuer.SignInAs(ssotest.Identity{Subject: "heidi-1", Email: "heidi@example.com", EmailVerified: true})
+
+	authURL, login, err := service.Start("mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A stolen code is useless without the PKCE verifier kept in the session
+	login.Verifier = "stolen-code-without-the-verifier-0123456789abcdef"
+	if _, err := service.Finish(context.Background(), "mock", login, state, code); err == nil {
+		t.Fatal("Finish() succeeded with the wrong PKCE verifier")
+	}
+}
@@ -0,0 +1,189 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrParentDeleted is returned when restoring an item whose board or list is still in the trash
+var ErrParentDeleted = errs.New(errs.Conflict, "parent_deleted", "the board or list this item belongs to is in the trash, restore it first")
+
+// Trash holds the boards, lists and cards a user has deleted, most recently deleted first.
+// The lists and cards of a deleted board are not listed separately; they come back with it.
+type Trash struct {
+	Boards []db.Board
+	Lists  []db.List
+	Cards  []db.Card
+}
+
+// Service handles the trash of deleted boards, lists and cards
+type Service struct {
+	queries *db.Queries
+	access  *access.Checker
+}
+
+// New creates a new trash service
+func New(queries *db.Queries) *Service {
+	return &Service{
+		queries: queries,
+		access:  access.New(queries),
+	}
+}
+
+// GetTrash gets the items the user has deleted
+func (s *Service) GetTrash(ctx context.Context, userID int32) (*Trash, error) {
+	deletedBy := pgtype.Int4{Int32: userID, Valid: true}
+
+	boards, err := s.queries.GetTrashedBoards(ctx, deletedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted boards: %w", err)
+	}
+
+	lists, err := s.queries.GetTrashedLists(ctx, deletedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted lists: %w", err)
+	}
+
+	cards, err := s.queries.GetTrashedCards(ctx, deletedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted cards: %w", err)
+	}
+
+	// Ensure we return empty slices instead of nil
+	trash := &Trash{Boards: boards, Lists: lists, Cards: cards}
+	if trash.Boards == nil {
+		trash.Boards = []db.Board{}
+	}
+	if trash.Lists == nil {
+		trash.Lists = []db.List{}
+	}
+	if trash.Cards == nil {
+		trash.Cards = []db.Card{}
+	}
+
+	return trash, nil
+}
+
+// RestoreBoard takes a board the user deleted out of the trash, along with its lists and cards.
+// The user must still be allowed to delete the board.
+func (s *Service) RestoreBoard(ctx context.Context, userID, boardID int32) (*db.Board, error) {
+	if _, err := s.access.DeletedBoard(ctx, userID, boardID, access.DeleteBoard); err != nil {
+		return nil, err
+	}
+
+	board, err := s.queries.RestoreBoard(ctx, db.RestoreBoardParams{
+		ID:     boardID,
+		UserID: pgtype.Int4{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to restore board: %w", err)
+	}
+
+	return &board, nil
+}
+
+// RestoreList takes a list the user deleted out of the trash, along with its cards.
+// Its board must not be in the trash, and the user must still be allowed to delete lists on it.
+func (s *Service) RestoreList(ctx context.Context, userID, listID int32) (*db.List, error) {
+	deletedBy := pgtype.Int4{Int32: userID, Valid: true}
+
+	trashed, err := s.queries.GetTrashedList(ctx, db.GetTrashedListParams{
+		ID:     listID,
+		UserID: deletedBy,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get deleted list: %w", err)
+	}
+	if trashed.BoardDeleted {
+		return nil, ErrParentDeleted
+	}
+
+	if _, err := s.access.Board(ctx, userID, trashed.BoardID, access.DeleteLists); err != nil {
+		return nil, err
+	}
+
+	list, err := s.queries.RestoreList(ctx, db.RestoreListParams{
+		ID:     listID,
+		UserID: deletedBy,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to restore list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// RestoreCard takes a card the user deleted out of the trash.
+// Its list and board must not be in the trash, and the user must still be allowed to delete cards on the board.
+func (s *Service) RestoreCard(ctx context.Context, userID, cardID int32) (*db.Card, error) {
+	deletedBy := pgtype.Int4{Int32: userID, Valid: true}
+
+	trashed, err := s.queries.GetTrashedCard(ctx, db.GetTrashedCardParams{
+		ID:     cardID,
+		UserID: deletedBy,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get deleted card: %w", err)
+	}
+	if trashed.ParentDeleted {
+		return nil, ErrParentDeleted
+	}
+
+	if _, err := s.access.Board(ctx, userID, trashed.BoardID, access.DeleteCards); err != nil {
+		return nil, err
+	}
+
+	card, err := s.queries.RestoreCard(ctx, db.RestoreCardParams{
+		ID:     cardID,
+		UserID: deletedBy,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, access.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to restore card: %w", err)
+	}
+
+	return &card, nil
+}
+
+// Purge permanently deletes everything that has been in the trash for longer than retention.
+// It returns the number of boards, lists and cards removed; their children go with them.
+func (s *Service) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-retention), Valid: true}
+
+	cards, err := s.queries.PurgeDeletedCards(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted cards: %w", err)
+	}
+
+	lists, err := s.queries.PurgeDeletedLists(ctx, before)
+	if err != nil {
+		return cards, fmt.Errorf("failed to purge deleted lists: %w", err)
+	}
+
+	boards, err := s.queries.PurgeDeletedBoards(ctx, before)
+	if err != nil {
+		return cards + lists, fmt.Errorf("failed to purge deleted boards: %w", err)
+	}
+
+	return cards + lists + boards, nil
+}
@@ -0,0 +1,291 @@
+package trash_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/db/dbtest"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/anubhav047/goboard/internal/services/board"
+	"github.com/anubhav047/goboard/internal/services/card"
+	"github.com/anubhav047/goboard/internal/services/list"
+	"github.com/anubhav047/goboard/internal/services/trash"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fixture is a user owning a board with one list holding one card
+type fixture struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	boards  *board.Service
+	lists   *list.Service
+	cards   *card.Service
+	trash   *trash.Service
+	userID  int32
+	boardID int32
+	listID  int32
+	cardID  int32
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	pool := dbtest.New(t)
+	queries := db.New(pool)
+	f := fixture{
+		pool:    pool,
+		queries: queries,
+		boards:  board.New(queries),
+		lists:   list.New(queries),
+		cards:   card.New(queries),
+		trash:   trash.New(queries),
+	}
+
+	user, err := queries.CreateUser(ctx, db.CreateUserParams{Name: "Test", Email: "test@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.userID = user.ID
+
+	b, err := f.boards.CreateBoard(ctx, "Board", "", user.ID, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.boardID = b.ID
+
+	l, err := f.lists.CreateList(ctx, user.ID, "List", b.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.listID = l.ID
+
+	c, err := f.cards.CreateCard(ctx, user.ID, "Card", "", l.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.cardID = c.ID
+
+	return f
+}
+
+func (f fixture) deleteBoard(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := f.boards.SetArchived(ctx, f.userID, f.boardID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.boards.DeleteBoard(ctx, f.userID, f.boardID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f fixture) deleteList(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := f.lists.SetArchived(ctx, f.userID, f.listID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.lists.DeleteList(ctx, f.userID, f.listID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f fixture) deleteCard(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := f.cards.SetArchived(ctx, f.userID, f.cardID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.cards.DeleteCard(ctx, f.userID, f.cardID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// backdate moves the deletion of every item in the trash the given time into the past
+func (f fixture) backdate(t *testing.T, age time.Duration) {
+	t.Helper()
+	for _, table := range []string{"boards", "lists", "cards"} {
+		_, err := f.pool.Exec(context.Background(),
+			"UPDATE "+table+" SET deleted_at = deleted_at - $1 * INTERVAL '1 second' WHERE deleted_at IS NOT NULL", int64(age.Seconds()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRestoreCard(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	f.deleteCard(t)
+
+	got, err := f.trash.GetTrash(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Cards) != 1 || got.Cards[0].ID != f.cardID {
+		t.Fatalf("trash has cards %v, want card %d", got.Cards, f.cardID)
+	}
+
+	restored, err := f.trash.RestoreCard(ctx, f.userID, f.cardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt.Valid || restored.DeletedBy.Valid {
+		t.Errorf("restored card is still marked deleted: %+v", restored)
+	}
+	// Restoring leaves the card archived, where it was deleted from
+	if !restored.ArchivedAt.Valid {
+		t.Error("restored card is no longer archived")
+	}
+
+	got, err = f.trash.GetTrash(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Cards) != 0 {
+		t.Errorf("trash still has cards %v", got.Cards)
+	}
+
+	if _, err := f.trash.RestoreCard(ctx, f.userID, f.cardID); !errors.Is(err, access.ErrNotFound) {
+		t.Errorf("restoring a card twice = %v, want ErrNotFound", err)
+	}
+}
+
+// TestRestoreChildOfDeleted checks that items come back only after what they belong to
+func TestRestoreChildOfDeleted(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	f.deleteCard(t)
+	f.deleteList(t)
+
+	if _, err := f.trash.RestoreCard(ctx, f.userID, f.cardID); !errors.Is(err, trash.ErrParentDeleted) {
+		t.Errorf("RestoreCard with its list in the trash = %v, want ErrParentDeleted", err)
+	}
+
+	f.deleteBoard(t)
+	if _, err := f.trash.RestoreList(ctx, f.userID, f.listID); !errors.Is(err, trash.ErrParentDeleted) {
+		t.Errorf("RestoreList with its board in the trash = %v, want ErrParentDeleted", err)
+	}
+
+	if _, err := f.trash.RestoreBoard(ctx, f.userID, f.boardID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.trash.RestoreList(ctx, f.userID, f.listID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.trash.RestoreCard(ctx, f.userID, f.cardID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRestoreBoardKeepsChildren checks that the lists and cards of a deleted board come back with it
+func TestRestoreBoardKeepsChildren(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	f.deleteBoard(t)
+
+	got, err := f.trash.GetTrash(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Boards) != 1 || len(got.Lists) != 0 || len(got.Cards) != 0 {
+		t.Errorf("trash has %d boards, %d lists and %d cards, want only the board", len(got.Boards), len(got.Lists), len(got.Cards))
+	}
+
+	if _, err := f.trash.RestoreBoard(ctx, f.userID, f.boardID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.boards.SetArchived(ctx, f.userID, f.boardID, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.cards.GetCardByID(ctx, f.userID, f.cardID); err != nil {
+		t.Errorf("card of the restored board: %v", err)
+	}
+}
+
+func TestRestoreOtherUsersTrash(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	f.deleteCard(t)
+
+	other, err := f.queries.CreateUser(ctx, db.CreateUserParams{Name: "Other", Email: "other@example.com", HashedPassword: "x", HasPassword: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.trash.RestoreCard(ctx, other.ID, f.cardID); !errors.Is(err, access.ErrNotFound) {
+		t.Errorf("RestoreCard by another user = %v, want ErrNotFound", err)
+	}
+	got, err := f.trash.GetTrash(ctx, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Cards) != 0 {
+		t.Errorf("other user's trash has cards %v", got.Cards)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	retention := 30 * 24 * time.Hour
+
+	f.deleteCard(t)
+	f.backdate(t, retention+time.Hour)
+
+	// A list deleted now is kept
+	extra, err := f.lists.CreateList(ctx, f.userID, "Extra", f.boardID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.lists.SetArchived(ctx, f.userID, extra.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.lists.DeleteList(ctx, f.userID, extra.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := f.trash.Purge(ctx, retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("Purge removed %d items, want 1", purged)
+	}
+
+	if _, err := f.queries.GetCardByID(ctx, f.cardID); !errs.IsNoRows(err) {
+		t.Errorf("purged card: %v, want no rows", err)
+	}
+	if _, err := f.trash.RestoreList(ctx, f.userID, extra.ID); err != nil {
+		t.Errorf("list deleted within the retention period: %v", err)
+	}
+}
+
+// TestPurgeBoard checks that purging a board removes its lists and cards with it
+func TestPurgeBoard(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	f.deleteBoard(t)
+	f.backdate(t, time.Hour)
+
+	purged, err := f.trash.Purge(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("Purge removed %d items, want 1", purged)
+	}
+
+	if _, err := f.queries.GetListByID(ctx, f.listID); !errs.IsNoRows(err) {
+		t.Errorf("list of the purged board: %v, want no rows", err)
+	}
+	if _, err := f.queries.GetCardByID(ctx, f.cardID); !errs.IsNoRows(err) {
+		t.Errorf("card of the purged board: %v, want no rows", err)
+	}
+}
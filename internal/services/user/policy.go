@@ -0,0 +1,77 @@
+package user
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/anubhav047/goboard/internal/errs"
+)
+
+// maxPasswordBytes is the longest password bcrypt can hash
+const maxPasswordBytes = 72
+
+// breachedPasswords is a bundled list of common and breached passwords, one per line
+//
+//go:embed breached_passwords.txt
+var breachedPasswords string
+
+var (
+	breachedOnce sync.Once
+	breachedSet  map[string]struct{}
+)
+
+// PasswordPolicy describes the rules new passwords must follow
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters
+	MinLength int
+	// CheckBreached rejects passwords found in the bundled breached password list
+	CheckBreached bool
+}
+
+// DefaultPasswordPolicy returns the policy used when none is configured
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     8,
+		CheckBreached: true,
+	}
+}
+
+// Check validates a new password for the account with the given email.
+// field is the name of the JSON field reported in the validation error.
+func (p PasswordPolicy) Check(field, password, email string) error {
+	switch {
+	case password == "":
+		return errs.Invalid(field, "password cannot be empty")
+	case utf8.RuneCountInString(password) < p.MinLength:
+		return errs.Invalid(field, fmt.Sprintf("password must be at least %d characters long", p.MinLength))
+	case len(password) > maxPasswordBytes:
+		return errs.Invalid(field, fmt.Sprintf("password must be at most %d bytes long", maxPasswordBytes))
+	case email != "" && strings.EqualFold(password, email):
+		return errs.Invalid(field, "password cannot be your email address")
+	case p.CheckBreached && isBreached(password):
+		return errs.Invalid(field, "this password is too common or has appeared in a data breach")
+	}
+	return nil
+}
+
+// isBreached reports whether the password is in the bundled list, ignoring case
+func isBreached(password string) bool {
+	breachedOnce.Do(func() {
+		breachedSet = make(map[string]struct{})
+		scanner := bufio.NewScanner(strings.NewReader(breachedPasswords))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			breachedSet[strings.ToLower(line)] = struct{}{}
+		}
+	})
+
+	_, ok := breachedSet[strings.ToLower(password)]
+	return ok
+}
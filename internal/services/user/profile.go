@@ -0,0 +1,134 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrEmailTaken is returned when another account already uses the email
+	ErrEmailTaken = errs.New(errs.Conflict, "email_taken", "email address is already in use")
+	// ErrWrongPassword is returned when the current password does not match
+	ErrWrongPassword = errs.New(errs.Forbidden, "wrong_password", "current password is incorrect")
+	// ErrPasswordNotSet is returned when an account created through single sign-on
+	// without a password tries an action that needs the current password
+	ErrPasswordNotSet = errs.New(errs.Conflict, "password_not_set", "set a password with a password reset link first")
+)
+
+// maxFieldLength is the length of the name and email columns
+const maxFieldLength = 255
+
+// UpdateProfile changes the user's name and/or email. Nil fields are left unchanged.
+// Changing the email needs the current password, since a stolen session could
+// otherwise take over the account through a password reset to the new address.
+// The old address is told about the change and the new one must be verified again.
+func (s *Service) UpdateProfile(ctx context.Context, userID int32, name, email *string, currentPassword string) (db.User, error) {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	params := db.UpdateUserProfileParams{
+		ID:    userID,
+		Name:  user.Name,
+		Email: user.Email,
+	}
+	if name != nil {
+		params.Name = strings.TrimSpace(*name)
+		if err := checkName(params.Name); err != nil {
+			return db.User{}, err
+		}
+	}
+	if email != nil {
+		params.Email = strings.TrimSpace(*email)
+		if err := checkEmail(params.Email); err != nil {
+			return db.User{}, err
+		}
+		if params.Email != user.Email {
+			if err := checkPassword(user, currentPassword); err != nil {
+				return db.User{}, err
+			}
+		}
+	}
+
+	updated, err := s.queries.UpdateUserProfile(ctx, params)
+	if err != nil {
+		if errs.IsUniqueViolation(err) {
+			return db.User{}, ErrEmailTaken
+		}
+		return db.User{}, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	// The change is saved even if the emails cannot be sent; the user can ask for a new link
+	if updated.Email != user.Email {
+		if err := s.sendVerification(ctx, updated); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", updated.ID, err)
+		}
+		if err := s.sendEmailChanged(ctx, user, updated.Email); err != nil {
+			log.Printf("Failed to send email change notice to user %d: %v", updated.ID, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// ChangePassword replaces the password after checking the current one.
+// Every other session of the user is revoked; keepToken is the current session's token.
+func (s *Service) ChangePassword(ctx context.Context, userID int32, currentPassword, newPassword, keepToken string) error {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := checkPassword(user, currentPassword); err != nil {
+		return err
+	}
+
+	if err := s.config.PasswordPolicy.Check("new_password", newPassword, user.Email); err != nil {
+		return err
+	}
+
+	return s.SetPassword(ctx, userID, newPassword, keepToken)
+}
+
+// checkPassword checks the user's current password
+func checkPassword(user db.User, password string) error {
+	if !user.HasPassword {
+		return ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// checkName validates a display name
+func checkName(name string) error {
+	if name == "" {
+		return errs.Invalid("name", "name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxFieldLength {
+		return errs.Invalid("name", fmt.Sprintf("name must be at most %d characters long", maxFieldLength))
+	}
+	return nil
+}
+
+// checkEmail validates an email address. Display names like "Jane <jane@example.com>" are rejected.
+func checkEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return errs.Invalid("email", "email address is invalid")
+	}
+	if len(email) > maxFieldLength {
+		return errs.Invalid("email", fmt.Sprintf("email must be at most %d characters long", maxFieldLength))
+	}
+	return nil
+}
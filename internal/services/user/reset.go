@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidResetToken is returned when a reset token is unknown, expired or already used
+var ErrInvalidResetToken = errs.New(errs.Validation, "invalid_reset_token", "invalid or expired password reset token")
+
+// RequestPasswordReset emails a single-use reset link to the user with the given email.
+// Unknown emails are ignored so callers cannot tell whether an account exists.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			log.Printf("Password reset requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	plain, hash, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	// Only the newest link is valid, and the email is sent inside the transaction
+	// so a failed delivery leaves no usable token behind
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := q.InvalidatePasswordResetTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		err := q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(s.config.ResetTokenTTL), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.mailer.Send(ctx, mail.Message{
+			To:      user.Email,
+			Subject: "Reset your GoBoard password",
+			Body: fmt.Sprintf("Someone asked to reset the password for your GoBoard account.\n\nOpen this link to choose a new password:\n%s/reset-password?token=%s\n\nThe link expires in %s and can only be used once. If you did not ask for this, you can ignore this email.",
+				s.config.BaseURL, plain, s.config.ResetTokenTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to request password reset: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token.
+// The token is consumed and every session of the user is revoked.
+func (s *Service) ResetPassword(ctx context.Context, resetToken, password string) error {
+	// Validate input
+	if err := s.config.PasswordPolicy.Check("password", password, ""); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		userID, err := q.ConsumePasswordResetToken(ctx, token.Hash(resetToken))
+		if err != nil {
+			return err
+		}
+
+		return setPassword(ctx, q, userID, hashedPassword, "")
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	return nil
+}
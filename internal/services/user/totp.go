@@ -0,0 +1,232 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/anubhav047/goboard/internal/totp"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// totpIssuer is the account name shown in authenticator apps
+const totpIssuer = "GoBoard"
+
+// recoveryCodeCount is how many recovery codes are issued when 2FA is enabled
+const recoveryCodeCount = 10
+
+var (
+	// ErrTOTPAlreadyEnabled is returned when enrolling a user who already uses 2FA
+	ErrTOTPAlreadyEnabled = errs.New(errs.Conflict, "totp_already_enabled", "two-factor authentication is already enabled")
+	// ErrTOTPNotEnabled is returned when the user does not use 2FA
+	ErrTOTPNotEnabled = errs.New(errs.Conflict, "totp_not_enabled", "two-factor authentication is not enabled")
+	// ErrTOTPNotEnrolled is returned when confirming 2FA before starting enrollment
+	ErrTOTPNotEnrolled = errs.New(errs.Conflict, "totp_not_enrolled", "two-factor enrollment has not been started")
+	// ErrInvalidTOTPCode is returned for wrong, reused or expired codes
+	ErrInvalidTOTPCode = errs.New(errs.Unauthorized, "invalid_totp_code", "invalid two-factor code")
+)
+
+// EnrollTOTP creates a new TOTP secret for the user.
+// It only takes effect once confirmed with a valid code through ConfirmTOTP.
+func (s *Service) EnrollTOTP(ctx context.Context, user db.User) (string, string, error) {
+	if user.TotpEnabledAt.Valid {
+		return "", "", ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.queries.SetPendingTOTPSecret(ctx, db.SetPendingTOTPSecretParams{
+		ID:         user.ID,
+		TotpSecret: pgtype.Text{String: secret, Valid: true},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	return secret, totp.URI(totpIssuer, user.Email, secret), nil
+}
+
+// ConfirmTOTP enables 2FA once the user has entered a valid code from their app.
+// It returns one-time recovery codes; only their hashes are stored.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID int32, code string) ([]string, error) {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.TotpEnabledAt.Valid {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if !user.TotpSecret.Valid {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	step, ok := totp.Validate(user.TotpSecret.String, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	var codes []string
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		rows, err := q.EnableTOTP(ctx, db.EnableTOTPParams{
+			ID:           userID,
+			TotpLastStep: pgtype.Int8{Int64: step, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrTOTPNotEnrolled
+		}
+
+		codes, err = replaceRecoveryCodes(ctx, q, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrTOTPNotEnrolled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	return codes, nil
+}
+
+// VerifyTOTP checks the second login step. It accepts a code from the
+// authenticator app or an unused recovery code.
+func (s *Service) VerifyTOTP(ctx context.Context, userID int32, code string) (db.User, error) {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return db.User{}, ErrInvalidCredentials
+		}
+		return db.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.TotpEnabledAt.Valid {
+		return db.User{}, ErrTOTPNotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return db.User{}, err
+	}
+
+	return user, nil
+}
+
+// DisableTOTP turns 2FA off. The user must re-authenticate with their
+// password and a current code or recovery code. Accounts created through single
+// sign-on that never set a password only need the code.
+func (s *Service) DisableTOTP(ctx context.Context, userID int32, password, code string) error {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.TotpEnabledAt.Valid {
+		return ErrTOTPNotEnabled
+	}
+
+	if user.HasPassword {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password)); err != nil {
+			return ErrInvalidCredentials
+		}
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := q.DisableTOTP(ctx, userID); err != nil {
+			return err
+		}
+		return q.DeleteRecoveryCodes(ctx, userID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable totp: %w", err)
+	}
+
+	return nil
+}
+
+// checkSecondFactor accepts a TOTP code that has not been used before, or an unused recovery code
+func (s *Service) checkSecondFactor(ctx context.Context, user db.User, code string) error {
+	if step, ok := totp.Validate(user.TotpSecret.String, code, time.Now()); ok {
+		rows, err := s.queries.UseTOTPStep(ctx, db.UseTOTPStepParams{
+			ID:           user.ID,
+			TotpLastStep: pgtype.Int8{Int64: step, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record totp code: %w", err)
+		}
+		if rows == 0 {
+			return ErrInvalidTOTPCode
+		}
+		return nil
+	}
+
+	rows, err := s.queries.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: token.Hash(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if rows == 0 {
+		return ErrInvalidTOTPCode
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and issues new ones
+func replaceRecoveryCodes(ctx context.Context, q *db.Queries, userID int32) ([]string, error) {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		err = q.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: token.Hash(normalizeRecoveryCode(code)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode returns a random code formatted like "abcde-fghij"
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode ignores case, dashes and spaces the user may have typed
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
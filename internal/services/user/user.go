@@ -2,30 +2,75 @@ package user
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/anubhav047/goboard/internal/db"
-	"github.com/jackc/pgx/v5"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/services/accesstoken"
+	"github.com/anubhav047/goboard/internal/services/session"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var ErrInvalidCredentials = errors.New("invalid email or password")
+var ErrInvalidCredentials = errs.New(errs.Unauthorized, "invalid_credentials", "invalid email or password")
+
+// Config holds the settings of the user Service.
+type Config struct {
+	// BaseURL is the address of the frontend, used to build links in emails.
+	BaseURL string
+	// ResetTokenTTL is how long a password reset link stays valid.
+	ResetTokenTTL time.Duration
+	// VerifyTokenTTL is how long an email verification link stays valid.
+	VerifyTokenTTL time.Duration
+	// PasswordPolicy is applied whenever a password is chosen.
+	PasswordPolicy PasswordPolicy
+}
 
 // Service handles the business logic for users
 type Service struct {
 	queries *db.Queries
+	mailer  mail.Mailer
+	config  Config
 }
 
 // New creates a new user Service.
-func New(queries *db.Queries) *Service {
+func New(queries *db.Queries, mailer mail.Mailer, config Config) *Service {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.ResetTokenTTL == 0 {
+		config.ResetTokenTTL = time.Hour
+	}
+	if config.VerifyTokenTTL == 0 {
+		config.VerifyTokenTTL = 24 * time.Hour
+	}
+	if config.PasswordPolicy == (PasswordPolicy{}) {
+		config.PasswordPolicy = DefaultPasswordPolicy()
+	}
+
 	return &Service{
 		queries: queries,
+		mailer:  mailer,
+		config:  config,
 	}
 }
 
 // Register creates a new user, hashes their password, and saves it to the database.
 func (s *Service) Register(ctx context.Context, name, email, password string) (db.User, error) {
+	name = strings.TrimSpace(name)
+	if err := checkName(name); err != nil {
+		return db.User{}, err
+	}
+	email = strings.TrimSpace(email)
+	if err := checkEmail(email); err != nil {
+		return db.User{}, err
+	}
+
+	if err := s.config.PasswordPolicy.Check("password", password, email); err != nil {
+		return db.User{}, err
+	}
+
 	//Hash the pw using bcrypt
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -37,14 +82,24 @@ func (s *Service) Register(ctx context.Context, name, email, password string) (d
 		Name:           name,
 		Email:          email,
 		HashedPassword: string(hashedPassword),
+		HasPassword:    true,
 	}
 
+	// Board invitations sent to the email are attached once it is verified, not here:
+	// anyone can register with an address they do not own.
 	user, err := s.queries.CreateUser(ctx, params)
 	if err != nil {
-		// TODO : check for db errors like duplicate email.
+		if errs.IsUniqueViolation(err) {
+			return db.User{}, ErrEmailTaken
+		}
 		return db.User{}, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// The account exists even if the email cannot be sent; the user can ask for a new link
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
 	return user, nil
 }
 
@@ -54,7 +109,7 @@ func (s *Service) Login(ctx context.Context, email, password string) (db.User, e
 	if err != nil {
 		// If the user is not found, pgx returns a special 'ErrNoRows'.
 		// We check for this and return our custom, generic error.
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errs.IsNoRows(err) {
 			return db.User{}, ErrInvalidCredentials
 		}
 		// For any other database error, we return a generic failure.
@@ -69,3 +124,44 @@ func (s *Service) Login(ctx context.Context, email, password string) (db.User, e
 
 	return user, nil
 }
+
+// SetPassword replaces a user's password and revokes all of their sessions
+// except the one with keepToken. Pass an empty keepToken to revoke every session.
+// Personal access tokens are revoked too, as whoever knew the old password could have created one.
+func (s *Service) SetPassword(ctx context.Context, userID int32, password, keepToken string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		return setPassword(ctx, q, userID, hashedPassword, keepToken)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return nil
+}
+
+// setPassword stores a new password hash, revokes sessions and access tokens and
+// invalidates outstanding reset links. It must run inside a transaction.
+func setPassword(ctx context.Context, q *db.Queries, userID int32, hashedPassword []byte, keepToken string) error {
+	err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		HashedPassword: string(hashedPassword),
+		ID:             userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := q.InvalidatePasswordResetTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := accesstoken.RevokeAll(ctx, q, userID); err != nil {
+		return err
+	}
+
+	return session.RevokeAll(ctx, q, userID, keepToken)
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/mail"
+	"github.com/anubhav047/goboard/internal/token"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrInvalidVerifyToken is returned when a verification token is unknown, expired or already used
+	ErrInvalidVerifyToken = errs.New(errs.Validation, "invalid_verify_token", "invalid or expired email verification token")
+	// ErrAlreadyVerified is returned when asking for a new link for a verified email
+	ErrAlreadyVerified = errs.New(errs.Conflict, "already_verified", "email address is already verified")
+)
+
+// VerifyEmail marks the user's email as verified using a verification token.
+// Board invitations sent to the address are attached to the user at the same time.
+func (s *Service) VerifyEmail(ctx context.Context, verifyToken string) (db.User, error) {
+	var user db.User
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		row, err := q.ConsumeEmailVerificationToken(ctx, token.Hash(verifyToken))
+		if err != nil {
+			return err
+		}
+
+		// The token only counts for the address it was sent to
+		user, err = q.MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{
+			ID:    row.UserID,
+			Email: row.Email,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.AttachInvitationsToUser(ctx, db.AttachInvitationsToUserParams{
+			InviteeID: pgtype.Int4{Int32: user.ID, Valid: true},
+			Email:     user.Email,
+		})
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return db.User{}, ErrInvalidVerifyToken
+		}
+		return db.User{}, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return user, nil
+}
+
+// ResendVerification sends a new verification link, invalidating the previous ones
+func (s *Service) ResendVerification(ctx context.Context, user db.User) error {
+	if user.EmailVerifiedAt.Valid {
+		return ErrAlreadyVerified
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// sendVerification creates a verification token for the user's current email and mails the link
+func (s *Service) sendVerification(ctx context.Context, user db.User) error {
+	plain, hash, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := q.InvalidateEmailVerificationTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		err := q.CreateEmailVerificationToken(ctx, db.CreateEmailVerificationTokenParams{
+			UserID:    user.ID,
+			Email:     user.Email,
+			TokenHash: hash,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(s.config.VerifyTokenTTL), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.mailer.Send(ctx, mail.Message{
+			To:      user.Email,
+			Subject: "Verify your GoBoard email address",
+			Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening this link:\n%s/verify-email?token=%s\n\nThe link expires in %s.",
+				user.Name, s.config.BaseURL, plain, s.config.VerifyTokenTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// sendEmailChanged tells the previous address of an account that its email was changed
+func (s *Service) sendEmailChanged(ctx context.Context, user db.User, newEmail string) error {
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Your GoBoard email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email address of your GoBoard account was changed from %s to %s.\n\nIf you did not make this change, someone else may have access to your account.",
+			user.Name, user.Email, newEmail),
+	})
+}
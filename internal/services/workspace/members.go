@@ -0,0 +1,183 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrUserNotFound is returned when the user being added does not exist
+	ErrUserNotFound = errs.New(errs.NotFound, "user_not_found", "user not found")
+	// ErrMemberNotFound is returned when the user is not a member of the workspace
+	ErrMemberNotFound = errs.New(errs.NotFound, "member_not_found", "user is not a member of this workspace")
+	// ErrAlreadyMember is returned when the user is already a member of the workspace
+	ErrAlreadyMember = errs.New(errs.Conflict, "already_member", "user is already a member of this workspace")
+	// ErrInvalidRole is returned for unknown roles or when trying to grant ownership
+	ErrInvalidRole = errs.Invalid("role", "role must be one of admin or member")
+	// ErrOwnerImmutable is returned when trying to change or remove the workspace owner
+	ErrOwnerImmutable = errs.New(errs.Conflict, "owner_immutable", "the workspace owner cannot be changed or removed")
+)
+
+// GetMembers gets all members of a workspace
+func (s *Service) GetMembers(ctx context.Context, userID, workspaceID int32) ([]db.GetWorkspaceMembersRow, error) {
+	if _, err := s.access.Workspace(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.queries.GetWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if members == nil {
+		return []db.GetWorkspaceMembersRow{}, nil
+	}
+
+	return members, nil
+}
+
+// AddMember adds an existing user, looked up by email, to a workspace with the given role
+func (s *Service) AddMember(ctx context.Context, userID, workspaceID int32, email, role string) (*db.WorkspaceMember, error) {
+	memberRole, err := parseMemberRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	member, err := s.queries.CreateWorkspaceMember(ctx, db.CreateWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        string(memberRole),
+	})
+	if err != nil {
+		if errs.IsUniqueViolation(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// UpdateMemberRole changes the role of a workspace member
+func (s *Service) UpdateMemberRole(ctx context.Context, userID, workspaceID, memberID int32, role string) (*db.WorkspaceMember, error) {
+	memberRole, err := parseMemberRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotOwner(ctx, workspaceID, memberID); err != nil {
+		return nil, err
+	}
+
+	// A demoted admin loses their cards on boards they can no longer see
+	var member db.WorkspaceMember
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		member, err = q.UpdateWorkspaceMemberRole(ctx, db.UpdateWorkspaceMemberRoleParams{
+			Role:        string(memberRole),
+			WorkspaceID: workspaceID,
+			UserID:      memberID,
+		})
+		if err != nil {
+			return err
+		}
+		return unassignWorkspace(ctx, q, workspaceID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workspace member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// RemoveMember removes a member from a workspace.
+// Members can always leave; removing others requires being a workspace admin.
+func (s *Service) RemoveMember(ctx context.Context, userID, workspaceID, memberID int32) error {
+	if userID == memberID {
+		if _, err := s.access.Workspace(ctx, userID, workspaceID); err != nil {
+			return err
+		}
+	} else if err := s.requireAdmin(ctx, userID, workspaceID); err != nil {
+		return err
+	}
+
+	if err := s.checkNotOwner(ctx, workspaceID, memberID); err != nil {
+		return err
+	}
+
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		err := q.DeleteWorkspaceMember(ctx, db.DeleteWorkspaceMemberParams{
+			WorkspaceID: workspaceID,
+			UserID:      memberID,
+		})
+		if err != nil {
+			return err
+		}
+		return unassignWorkspace(ctx, q, workspaceID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+
+	return nil
+}
+
+// checkNotOwner makes sure the member exists and is not the workspace owner
+func (s *Service) checkNotOwner(ctx context.Context, workspaceID, memberID int32) error {
+	member, err := s.queries.GetWorkspaceMember(ctx, db.GetWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      memberID,
+	})
+	if err != nil {
+		if errs.IsNoRows(err) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("failed to get workspace member: %w", err)
+	}
+
+	if access.WorkspaceRole(member.Role) == access.WorkspaceOwner {
+		return ErrOwnerImmutable
+	}
+
+	return nil
+}
+
+// parseMemberRole validates a role that can be granted to a member
+func parseMemberRole(role string) (access.WorkspaceRole, error) {
+	r, err := access.ParseWorkspaceRole(role)
+	if err != nil || r == access.WorkspaceOwner {
+		return "", ErrInvalidRole
+	}
+	return r, nil
+}
+
+// unassignWorkspace unassigns cards on the workspace's boards from users who can no longer see them
+func unassignWorkspace(ctx context.Context, q *db.Queries, workspaceID int32) error {
+	boardIDs, err := q.GetWorkspaceBoardIDs(ctx, pgtype.Int4{Int32: workspaceID, Valid: true})
+	if err != nil {
+		return err
+	}
+	return q.UnassignUsersWithoutAccess(ctx, boardIDs)
+}
@@ -0,0 +1,182 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anubhav047/goboard/internal/db"
+	"github.com/anubhav047/goboard/internal/errs"
+	"github.com/anubhav047/goboard/internal/services/access"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrEmptyName is returned when a workspace is created or renamed without a name
+	ErrEmptyName = errs.Invalid("name", "workspace name cannot be empty")
+	// ErrNotAdmin is returned when a plain member tries to manage the workspace
+	ErrNotAdmin = errs.New(errs.Forbidden, "not_workspace_admin", "only workspace admins can perform this action")
+	// ErrNotOwner is returned when someone other than the owner tries to delete the workspace
+	ErrNotOwner = errs.New(errs.Forbidden, "not_workspace_owner", "only the workspace owner can delete it")
+)
+
+// Service handles workspace-related business logic
+type Service struct {
+	queries *db.Queries
+	access  *access.Checker
+}
+
+// New creates a new workspace service
+func New(queries *db.Queries) *Service {
+	return &Service{
+		queries: queries,
+		access:  access.New(queries),
+	}
+}
+
+// CreateWorkspace creates a new workspace and makes its creator the owner
+func (s *Service) CreateWorkspace(ctx context.Context, userID int32, name, description string) (*db.Workspace, error) {
+	// Validate input
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	var workspace db.Workspace
+	err := s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		workspace, err = q.CreateWorkspace(ctx, db.CreateWorkspaceParams{
+			Name:        name,
+			Description: pgtype.Text{String: description, Valid: true},
+			CreatedBy:   userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateWorkspaceMember(ctx, db.CreateWorkspaceMemberParams{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        string(access.WorkspaceOwner),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// GetUserWorkspaces gets all workspaces the user is a member of, along with their role
+func (s *Service) GetUserWorkspaces(ctx context.Context, userID int32) ([]db.GetWorkspacesByUserRow, error) {
+	workspaces, err := s.queries.GetWorkspacesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user workspaces: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if workspaces == nil {
+		return []db.GetWorkspacesByUserRow{}, nil
+	}
+
+	return workspaces, nil
+}
+
+// GetWorkspaceByID gets a single workspace by ID
+func (s *Service) GetWorkspaceByID(ctx context.Context, userID, workspaceID int32) (*db.Workspace, error) {
+	if _, err := s.access.Workspace(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.queries.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// UpdateWorkspace updates a workspace's name and description
+func (s *Service) UpdateWorkspace(ctx context.Context, userID, workspaceID int32, name, description string) (*db.Workspace, error) {
+	// Validate input
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	if err := s.requireAdmin(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.queries.UpdateWorkspace(ctx, db.UpdateWorkspaceParams{
+		Name:        name,
+		Description: pgtype.Text{String: description, Valid: true},
+		ID:          workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// DeleteWorkspace deletes a workspace. Its boards become personal boards again.
+func (s *Service) DeleteWorkspace(ctx context.Context, userID, workspaceID int32) error {
+	role, err := s.access.Workspace(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if role != access.WorkspaceOwner {
+		return ErrNotOwner
+	}
+
+	// Workspace members lose their cards on boards they could only see through the workspace
+	err = s.queries.ExecTx(ctx, func(q *db.Queries) error {
+		boardIDs, err := q.GetWorkspaceBoardIDs(ctx, pgtype.Int4{Int32: workspaceID, Valid: true})
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteWorkspace(ctx, workspaceID); err != nil {
+			return err
+		}
+		return q.UnassignUsersWithoutAccess(ctx, boardIDs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkspaceBoards gets the workspace boards visible to the user.
+// Admins see every board; members see workspace-visible boards and boards they belong to.
+func (s *Service) GetWorkspaceBoards(ctx context.Context, userID, workspaceID int32) ([]db.Board, error) {
+	if _, err := s.access.Workspace(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	boards, err := s.queries.GetWorkspaceBoards(ctx, db.GetWorkspaceBoardsParams{
+		WorkspaceID: pgtype.Int4{Int32: workspaceID, Valid: true},
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace boards: %w", err)
+	}
+
+	// Ensure we return an empty slice instead of nil
+	if boards == nil {
+		return []db.Board{}, nil
+	}
+
+	return boards, nil
+}
+
+// requireAdmin checks that the user is an owner or admin of the workspace
+func (s *Service) requireAdmin(ctx context.Context, userID, workspaceID int32) error {
+	role, err := s.access.Workspace(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !role.IsAdmin() {
+		return ErrNotAdmin
+	}
+	return nil
+}
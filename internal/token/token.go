@@ -0,0 +1,69 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Generate returns a random URL-safe token and its hash.
+// Only the hash should be stored; the token itself is handed to the user.
+func Generate() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+// Hash returns the hex-encoded SHA-256 hash of a token
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Signer creates tokens carrying an HMAC signature, so forged tokens
+// can be rejected without a database lookup.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner creates a new Signer with the given secret
+func NewSigner(secret []byte) *Signer {
+	return &Signer{
+		secret: secret,
+	}
+}
+
+// Generate returns a signed random token and its hash
+func (s *Signer) Generate() (string, string, error) {
+	value, _, err := Generate()
+	if err != nil {
+		return "", "", err
+	}
+
+	token := value + "." + s.sign(value)
+	return token, Hash(token), nil
+}
+
+// Verify reports whether the token carries a valid signature
+func (s *Signer) Verify(token string) bool {
+	value, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.sign(value)))
+}
+
+// sign returns the base64-encoded HMAC-SHA256 of the value
+func (s *Signer) sign(value string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
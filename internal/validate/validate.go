@@ -0,0 +1,204 @@
+// Package validate checks request structs against rules declared in struct tags:
+//
+//	Name string `json:"name" validate:"required,max=255"`
+//
+// Supported rules are required, min=N, max=N and oneof=a b c. For strings min and max
+// count characters, for numbers they bound the value and for slices and maps the length.
+// Fields are reported by their JSON name.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors maps JSON field names to what is wrong with them
+type Errors map[string]string
+
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field + " " + e[field]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct validates the fields of a struct, or a pointer to one.
+// It returns Errors when any rule is broken and nil otherwise.
+// Malformed tags are programming errors and panic.
+func Struct(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %T is not a struct", v))
+	}
+
+	errs := Errors{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("validate")
+		if tag == "" || !f.IsExported() {
+			continue
+		}
+
+		if msg := checkField(rv.Field(i), tag); msg != "" {
+			errs[fieldName(f)] = msg
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkField applies the rules in tag to a field and returns the first failure
+func checkField(v reflect.Value, tag string) string {
+	// Optional fields are pointers; absent ones only fail required
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if hasRule(tag, "required") {
+				return "is required"
+			}
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		var msg string
+		switch name {
+		case "required":
+			msg = checkRequired(v)
+		case "min":
+			msg = checkBound(v, parseBound(rule, arg), true)
+		case "max":
+			msg = checkBound(v, parseBound(rule, arg), false)
+		case "oneof":
+			msg = checkOneOf(v, strings.Fields(arg))
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q", rule))
+		}
+		if msg != "" {
+			return msg
+		}
+	}
+
+	return ""
+}
+
+func checkRequired(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		if strings.TrimSpace(v.String()) == "" {
+			return "is required"
+		}
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return "is required"
+		}
+	default:
+		if v.IsZero() {
+			return "is required"
+		}
+	}
+	return ""
+}
+
+// checkBound checks a lower bound when min is true, otherwise an upper bound
+func checkBound(v reflect.Value, bound int64, min bool) string {
+	var n int64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = int64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = int64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	default:
+		panic(fmt.Sprintf("validate: min and max do not apply to %s", v.Kind()))
+	}
+
+	if unit == " characters" && bound == 1 {
+		unit = " character"
+	}
+	if min && n < bound {
+		return fmt.Sprintf("must be at least %d%s", bound, unit)
+	}
+	if !min && n > bound {
+		return fmt.Sprintf("must be at most %d%s", bound, unit)
+	}
+	return ""
+}
+
+// checkOneOf checks a string, or every string in a slice, against the allowed values.
+// Empty strings pass so optional fields can fall back to a default.
+func checkOneOf(v reflect.Value, allowed []string) string {
+	values := []string{}
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() != "" {
+			values = append(values, v.String())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i).String())
+		}
+	default:
+		panic(fmt.Sprintf("validate: oneof does not apply to %s", v.Kind()))
+	}
+
+	for _, value := range values {
+		if !contains(allowed, value) {
+			return "must be one of " + strings.Join(allowed, ", ")
+		}
+	}
+	return ""
+}
+
+func parseBound(rule, arg string) int64 {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate: invalid rule %q", rule))
+	}
+	return n
+}
+
+func hasRule(tag, name string) bool {
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the JSON name of a struct field
+func fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type request struct {
+	Name     string   `json:"name" validate:"required,max=5"`
+	Title    *string  `json:"title" validate:"min=1,max=3"`
+	Position int32    `json:"position" validate:"min=0"`
+	Color    string   `json:"color" validate:"oneof=red green"`
+	Scopes   []string `json:"scopes" validate:"required,oneof=read write"`
+	Untagged string   `json:"untagged"`
+	NoJSON   string   `validate:"required"`
+	internal string   `validate:"required"`
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// valid returns a request that passes every rule
+func valid() request {
+	return request{Name: "board", Position: 1, Scopes: []string{"read"}, NoJSON: "x"}
+}
+
+func TestStructValid(t *testing.T) {
+	r := valid()
+	if err := Struct(&r); err != nil {
+		t.Errorf("Struct = %v, want nil", err)
+	}
+	if err := Struct(r); err != nil {
+		t.Errorf("Struct by value = %v, want nil", err)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *request)
+		want   Errors
+	}{
+		{"blank required string", func(r *request) { r.Name = "  " }, Errors{"name": "is required"}},
+		{"string too long", func(r *request) { r.Name = "boards" }, Errors{"name": "must be at most 5 characters"}},
+		{"characters not bytes", func(r *request) { r.Name = "ÄÖÜäö" }, nil},
+		{"absent optional pointer", func(r *request) { r.Title = nil }, nil},
+		{"empty pointer below min", func(r *request) { r.Title = ptr("") }, Errors{"title": "must be at least 1 character"}},
+		{"pointer too long", func(r *request) { r.Title = ptr("abcd") }, Errors{"title": "must be at most 3 characters"}},
+		{"number below min", func(r *request) { r.Position = -1 }, Errors{"position": "must be at least 0"}},
+		{"empty oneof falls back", func(r *request) { r.Color = "" }, nil},
+		{"value not in oneof", func(r *request) { r.Color = "blue" }, Errors{"color": "must be one of red, green"}},
+		{"empty required slice", func(r *request) { r.Scopes = nil }, Errors{"scopes": "is required"}},
+		{"slice value not in oneof", func(r *request) { r.Scopes = []string{"read", "admin"} }, Errors{"scopes": "must be one of read, write"}},
+		{"field without json name", func(r *request) { r.NoJSON = "" }, Errors{"NoJSON": "is required"}},
+		{"several fields", func(r *request) { r.Name = ""; r.Position = -1 }, Errors{"name": "is required", "position": "must be at least 0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+
+			err := Struct(&r)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Struct = %v, want nil", err)
+				}
+				return
+			}
+
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("Struct = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStructFirstFailure checks that each field reports only its first broken rule
+func TestStructFirstFailure(t *testing.T) {
+	r := valid()
+	r.Scopes = nil
+	err := Struct(&r)
+	if err == nil || err.Error() != "scopes is required" {
+		t.Errorf("Struct = %v, want scopes is required", err)
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	err := Errors{"name": "is required", "color": "must be one of red, green"}
+	want := "color must be one of red, green; name is required"
+	if got := err.Error(); got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+}
+
+func TestStructPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"not a struct", "name"},
+		{"unknown rule", struct {
+			Name string `validate:"email"`
+		}{}},
+		{"invalid bound", struct {
+			Name string `validate:"max=ten"`
+		}{}},
+		{"bound on bool", struct {
+			Done bool `validate:"max=1"`
+		}{}},
+		{"oneof on number", struct {
+			Count int `validate:"oneof=1 2"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Struct did not panic")
+				}
+			}()
+			Struct(tt.v)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_board_members_user_id;
+DROP TABLE IF EXISTS board_members;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE board_members (
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'editor', 'viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (board_id, user_id)
+);
+
+-- Index for faster queries when finding boards by member
+CREATE INDEX idx_board_members_user_id ON board_members(user_id);
+
+-- Existing board creators become the owners of their boards
+INSERT INTO board_members (board_id, user_id, role)
+SELECT id, created_by, 'owner' FROM boards;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_board_invitations_invitee_id;
+DROP INDEX IF EXISTS idx_board_invitations_email;
+DROP INDEX IF EXISTS idx_board_invitations_board_id;
+DROP TABLE IF EXISTS board_invitations;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE board_invitations (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'editor', 'viewer')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invitee_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for faster queries when finding invitations by board
+CREATE INDEX idx_board_invitations_board_id ON board_invitations(board_id);
+
+-- Index for attaching invitations when a user registers
+CREATE INDEX idx_board_invitations_email ON board_invitations(LOWER(email));
+
+-- Index for finding invitations attached to a user
+CREATE INDEX idx_board_invitations_invitee_id ON board_invitations(invitee_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_boards_workspace_id;
+ALTER TABLE boards DROP COLUMN IF EXISTS visibility;
+ALTER TABLE boards DROP COLUMN IF EXISTS workspace_id;
+DROP INDEX IF EXISTS idx_workspace_members_user_id;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE workspace_members (
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- Index for faster queries when finding workspaces by member
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- Boards can optionally belong to a workspace. Deleting the workspace turns them back into personal boards.
+ALTER TABLE boards ADD COLUMN workspace_id INTEGER REFERENCES workspaces(id) ON DELETE SET NULL;
+ALTER TABLE boards ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'workspace'));
+
+-- Index for faster queries when finding boards by workspace
+CREATE INDEX idx_boards_workspace_id ON boards(workspace_id);
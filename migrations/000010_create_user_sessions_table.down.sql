@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_sessions_user_id;
+DROP TABLE IF EXISTS user_sessions;
\ No newline at end of file
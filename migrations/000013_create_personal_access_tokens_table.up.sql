@@ -0,0 +1,14 @@
+CREATE TABLE personal_access_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (scopes <@ ARRAY['read', 'write', 'admin']::TEXT[] AND cardinality(scopes) > 0)
+);
+
+-- Index for faster queries when listing a user's tokens
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
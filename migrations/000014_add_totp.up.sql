@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMPTZ;
+-- Time step of the last accepted code, so a code cannot be replayed
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
+
+CREATE TABLE totp_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for faster queries when replacing a user's codes
+CREATE INDEX idx_totp_recovery_codes_user_id ON totp_recovery_codes(user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_security_events_ip_address;
+DROP INDEX IF EXISTS idx_security_events_user_id;
+DROP TABLE IF EXISTS security_events;
+DROP TABLE IF EXISTS login_attempts;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Failed login counters, shared by every server instance
+CREATE TABLE login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL
+);
+
+-- Audit log of security related events such as failed logins and lockouts
+CREATE TABLE security_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for faster queries when auditing a user or an address
+CREATE INDEX idx_security_events_user_id ON security_events(user_id, created_at);
+CREATE INDEX idx_security_events_ip_address ON security_events(ip_address, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE cards DROP CONSTRAINT IF EXISTS unique_card_position_per_list;
+ALTER TABLE cards ADD CONSTRAINT unique_card_position_per_list UNIQUE (list_id, position);
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Renumber cards densely from 0 so positions match their order
+ALTER TABLE cards DROP CONSTRAINT unique_card_position_per_list;
+
+UPDATE cards c SET position = ranked.position
+FROM (
+    SELECT id, (ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY position, id) - 1)::int AS position FROM cards
+) ranked
+WHERE c.id = ranked.id AND c.position <> ranked.position;
+
+-- Deferrable so a reorder can shift cards past each other inside one transaction
+ALTER TABLE cards ADD CONSTRAINT unique_card_position_per_list UNIQUE (list_id, position) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,16 @@
+ALTER TABLE lists ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+UPDATE lists l SET position = ranked.position
+FROM (SELECT id, (ROW_NUMBER() OVER (PARTITION BY board_id ORDER BY rank) - 1)::int AS position FROM lists) ranked
+WHERE l.id = ranked.id;
+ALTER TABLE lists DROP CONSTRAINT IF EXISTS unique_list_rank_per_board;
+ALTER TABLE lists DROP COLUMN rank;
+CREATE INDEX idx_lists_board_position ON lists(board_id, position);
+
+ALTER TABLE cards ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+UPDATE cards c SET position = ranked.position
+FROM (SELECT id, (ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY rank) - 1)::int AS position FROM cards) ranked
+WHERE c.id = ranked.id;
+ALTER TABLE cards DROP CONSTRAINT IF EXISTS unique_card_rank_per_list;
+ALTER TABLE cards DROP COLUMN rank;
+CREATE INDEX idx_cards_list_position ON cards(list_id, position);
+ALTER TABLE cards ADD CONSTRAINT unique_card_position_per_list UNIQUE (list_id, position) DEFERRABLE INITIALLY IMMEDIATE;
\ No newline at end of file
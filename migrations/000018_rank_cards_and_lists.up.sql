@@ -0,0 +1,58 @@
+-- Cards and lists are ordered by rank keys (see internal/rank) instead of integer positions,
+-- so moving an item only rewrites its own row. Existing positions become six-digit
+-- base-62 keys spaced 3844 apart, without trailing zeros.
+ALTER TABLE cards ADD COLUMN rank TEXT COLLATE "C";
+
+UPDATE cards c SET rank = ranked.rank
+FROM (
+    SELECT id, rtrim(
+        substr(digits, (n / 916132832 % 62)::int + 1, 1) ||
+        substr(digits, (n / 14776336 % 62)::int + 1, 1) ||
+        substr(digits, (n / 238328 % 62)::int + 1, 1) ||
+        substr(digits, (n / 3844 % 62)::int + 1, 1) ||
+        substr(digits, (n / 62 % 62)::int + 1, 1) ||
+        substr(digits, (n % 62)::int + 1, 1),
+        '0'
+    ) AS rank
+    FROM (
+        SELECT id,
+            ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY position, id) * 3844 AS n,
+            '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz'::text AS digits
+        FROM cards
+    ) numbered
+) ranked
+WHERE c.id = ranked.id;
+
+ALTER TABLE cards ALTER COLUMN rank SET NOT NULL;
+ALTER TABLE cards DROP CONSTRAINT unique_card_position_per_list;
+DROP INDEX IF EXISTS idx_cards_list_position;
+ALTER TABLE cards DROP COLUMN position;
+-- Deferrable so ranks can be rewritten in one statement when rebalancing
+ALTER TABLE cards ADD CONSTRAINT unique_card_rank_per_list UNIQUE (list_id, rank) DEFERRABLE INITIALLY IMMEDIATE;
+
+ALTER TABLE lists ADD COLUMN rank TEXT COLLATE "C";
+
+UPDATE lists l SET rank = ranked.rank
+FROM (
+    SELECT id, rtrim(
+        substr(digits, (n / 916132832 % 62)::int + 1, 1) ||
+        substr(digits, (n / 14776336 % 62)::int + 1, 1) ||
+        substr(digits, (n / 238328 % 62)::int + 1, 1) ||
+        substr(digits, (n / 3844 % 62)::int + 1, 1) ||
+        substr(digits, (n / 62 % 62)::int + 1, 1) ||
+        substr(digits, (n % 62)::int + 1, 1),
+        '0'
+    ) AS rank
+    FROM (
+        SELECT id,
+            ROW_NUMBER() OVER (PARTITION BY board_id ORDER BY position, id) * 3844 AS n,
+            '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz'::text AS digits
+        FROM lists
+    ) numbered
+) ranked
+WHERE l.id = ranked.id;
+
+ALTER TABLE lists ALTER COLUMN rank SET NOT NULL;
+DROP INDEX IF EXISTS idx_lists_board_position;
+ALTER TABLE lists DROP COLUMN position;
+ALTER TABLE lists ADD CONSTRAINT unique_list_rank_per_board UNIQUE (board_id, rank) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,3 @@
+ALTER TABLE cards DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE lists DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE boards DROP COLUMN IF EXISTS archived_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Archived items are hidden from the default views and must be archived before they can be deleted
+ALTER TABLE boards ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE lists ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE cards ADD COLUMN archived_at TIMESTAMPTZ;
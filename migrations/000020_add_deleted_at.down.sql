@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_cards_deleted_at;
+DROP INDEX IF EXISTS idx_lists_deleted_at;
+DROP INDEX IF EXISTS idx_boards_deleted_at;
+
+ALTER TABLE cards DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE cards DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE lists DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE lists DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE boards DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE boards DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Deleted boards, lists and cards stay in the trash of the user who deleted them until they are
+-- restored or purged. Children of a deleted item are hidden with it and come back when it is restored.
+ALTER TABLE boards ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE boards ADD COLUMN deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE lists ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE lists ADD COLUMN deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE cards ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE cards ADD COLUMN deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_boards_deleted_at ON boards(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_lists_deleted_at ON lists(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_cards_deleted_at ON cards(deleted_at) WHERE deleted_at IS NOT NULL;
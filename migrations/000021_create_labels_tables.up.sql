@@ -0,0 +1,19 @@
+CREATE TABLE labels (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    color VARCHAR(7) NOT NULL CHECK (color ~ '^#[0-9a-f]{6}$'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_label_name_per_board UNIQUE (board_id, name)
+);
+
+CREATE TABLE card_labels (
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    label_id INTEGER NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (card_id, label_id)
+);
+
+-- Index for faster queries when filtering cards by label
+CREATE INDEX idx_card_labels_label_id ON card_labels(label_id);
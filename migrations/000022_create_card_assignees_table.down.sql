@@ -0,0 +1 @@
+DROP TABLE IF EXISTS card_assignees;
\ No newline at end of file
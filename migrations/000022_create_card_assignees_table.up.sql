@@ -0,0 +1,10 @@
+CREATE TABLE card_assignees (
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (card_id, user_id)
+);
+
+-- Index for faster queries when finding the cards assigned to a user
+CREATE INDEX idx_card_assignees_user_id ON card_assignees(user_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_cards_due_at;
+
+ALTER TABLE cards DROP CONSTRAINT IF EXISTS card_completed_at;
+ALTER TABLE cards DROP CONSTRAINT IF EXISTS card_start_before_due;
+
+ALTER TABLE cards DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE cards DROP COLUMN IF EXISTS completed;
+ALTER TABLE cards DROP COLUMN IF EXISTS due_at;
+ALTER TABLE cards DROP COLUMN IF EXISTS start_at;
\ No newline at end of file
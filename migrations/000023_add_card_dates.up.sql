@@ -0,0 +1,11 @@
+-- Card dates are stored as timestamptz and always handled in UTC
+ALTER TABLE cards ADD COLUMN start_at TIMESTAMPTZ;
+ALTER TABLE cards ADD COLUMN due_at TIMESTAMPTZ;
+ALTER TABLE cards ADD COLUMN completed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE cards ADD COLUMN completed_at TIMESTAMPTZ;
+
+ALTER TABLE cards ADD CONSTRAINT card_start_before_due CHECK (start_at <= due_at);
+ALTER TABLE cards ADD CONSTRAINT card_completed_at CHECK (completed = (completed_at IS NOT NULL));
+
+-- Index for finding cards that are due soon or overdue
+CREATE INDEX idx_cards_due_at ON cards(due_at) WHERE due_at IS NOT NULL AND NOT completed;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS card_reminders;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- A reminder fires offset_seconds before its card is due. sent_for_due_at is the due date
+-- it last fired for, so moving the due date re-arms it.
+CREATE TABLE card_reminders (
+    id SERIAL PRIMARY KEY,
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    offset_seconds INTEGER NOT NULL CHECK (offset_seconds > 0),
+    sent_for_due_at TIMESTAMPTZ,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_reminder_offset_per_card UNIQUE (card_id, offset_seconds)
+);
@@ -0,0 +1,8 @@
+-- Accounts created through single sign-on get a random password hash and no usable password
+ALTER TABLE users ADD COLUMN has_password BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Provisioned accounts were created in the same transaction as their identity. Those that
+-- never used or invalidated a reset link cannot have chosen a password since.
+UPDATE users u SET has_password = FALSE
+WHERE EXISTS (SELECT 1 FROM user_identities i WHERE i.user_id = u.id AND i.created_at = u.created_at)
+  AND NOT EXISTS (SELECT 1 FROM password_reset_tokens t WHERE t.user_id = u.id AND t.used_at IS NOT NULL);
@@ -0,0 +1,2 @@
+-- When a throttled login was last recorded, so the event is logged once per backoff
+ALTER TABLE login_attempts ADD COLUMN throttled_at TIMESTAMPTZ;